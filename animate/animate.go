@@ -0,0 +1,171 @@
+// Package animate provides time-based tweens and easing for smooth
+// transitions: progress bars filling, scroll positions gliding, colors
+// fading between node.RGB values.
+//
+// A Tween is a value kept in the model. It records where it started
+// and when, so its value is a pure function of the time; nothing needs
+// updating between frames. Start one from a Clock, ask the runtime for
+// frames with app.RequestFrame, and read the tween at each
+// app.FrameMsg's Time until it is done:
+//
+//	case app.KeyMsg:
+//		m.bar = animate.Float(0, 1, m.clock.Now(), time.Second, animate.EaseOut)
+//		return app.WithCmd(m, app.RequestFrame())
+//	case app.FrameMsg:
+//		m.now = msg.Time
+//		return animate.Continue(m, msg.Time, m.bar)
+//
+//	// in View
+//	component.Progress(m.bar.At(m.now), 40, fg, bg)
+//
+// Tests drive the same code with a Fake clock and its Frame method.
+package animate
+
+import (
+	"math"
+	"time"
+
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/node"
+)
+
+// Animation is anything that runs until a point in time, such as a
+// Tween. See Running and Continue.
+type Animation interface {
+	Done(now time.Time) bool
+}
+
+// Tween moves a value from one end to the other over a duration. The
+// zero Tween is done and holds the zero value.
+type Tween[T any] struct {
+	from, to T
+	start    time.Time
+	duration time.Duration
+	ease     Easing
+	lerp     func(a, b T, t float64) T
+}
+
+// New returns a Tween from from to to, starting at start, that
+// interpolates with lerp. ease may be nil for Linear.
+func New[T any](from, to T, start time.Time, d time.Duration, ease Easing, lerp func(a, b T, t float64) T) Tween[T] {
+	if ease == nil {
+		ease = Linear
+	}
+	return Tween[T]{from: from, to: to, start: start, duration: d, ease: ease, lerp: lerp}
+}
+
+// Float tweens a float64.
+func Float(from, to float64, start time.Time, d time.Duration, ease Easing) Tween[float64] {
+	return New(from, to, start, d, ease, Lerp)
+}
+
+// Int tweens an int (a scroll offset, a column), rounding to the
+// nearest step.
+func Int(from, to int, start time.Time, d time.Duration, ease Easing) Tween[int] {
+	return New(from, to, start, d, ease, LerpInt)
+}
+
+// Color fades between two colors; see LerpColor.
+func Color(from, to node.Color, start time.Time, d time.Duration, ease Easing) Tween[node.Color] {
+	return New(from, to, start, d, ease, LerpColor)
+}
+
+// Progress returns the eased progress at now: 0 before the start, 1
+// once done.
+func (tw Tween[T]) Progress(now time.Time) float64 {
+	if tw.Done(now) {
+		return 1
+	}
+	elapsed := now.Sub(tw.start)
+	if elapsed <= 0 {
+		return 0
+	}
+	return tw.ease(float64(elapsed) / float64(tw.duration))
+}
+
+// At returns the value at now.
+func (tw Tween[T]) At(now time.Time) T {
+	if tw.Done(now) || tw.lerp == nil {
+		return tw.to
+	}
+	return tw.lerp(tw.from, tw.to, tw.Progress(now))
+}
+
+// Done reports whether the tween has reached its end by now.
+func (tw Tween[T]) Done(now time.Time) bool {
+	return !now.Before(tw.start.Add(tw.duration))
+}
+
+// To returns the value the tween ends at.
+func (tw Tween[T]) To() T { return tw.to }
+
+// Retarget returns a tween from the value at now to a new target over
+// the same duration and easing, so a value already in motion (a scroll
+// position the user keeps scrolling) heads somewhere else smoothly.
+func (tw Tween[T]) Retarget(now time.Time, to T) Tween[T] {
+	tw.from, tw.to, tw.start = tw.At(now), to, now
+	return tw
+}
+
+// Lerp interpolates linearly between a and b.
+func Lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// LerpInt interpolates between a and b, rounding to the nearest int.
+func LerpInt(a, b int, t float64) int {
+	return int(math.Round(Lerp(float64(a), float64(b), t)))
+}
+
+// LerpColor blends two node.RGB colors channel by channel. Palette and
+// default colors have no RGB value to blend, so between those the
+// color switches halfway.
+func LerpColor(a, b node.Color, t float64) node.Color {
+	if !a.IsRGB() || !b.IsRGB() {
+		if t < 0.5 {
+			return a
+		}
+		return b
+	}
+	ar, ag, ab := a.RGBValues()
+	br, bg, bb := b.RGBValues()
+	return node.RGB(lerpByte(ar, br, t), lerpByte(ag, bg, t), lerpByte(ab, bb, t))
+}
+
+// lerpByte interpolates one color channel, clamping overshoot from
+// back and elastic easings.
+func lerpByte(a, b uint8, t float64) uint8 {
+	v := math.Round(Lerp(float64(a), float64(b), t))
+	return uint8(min(max(v, 0), 255))
+}
+
+// Running reports whether any of anims is still running at now.
+func Running(now time.Time, anims ...Animation) bool {
+	for _, a := range anims {
+		if !a.Done(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// Continue returns model with an app.RequestFrame while any of anims
+// is still running at now, so frames keep coming until the last one
+// ends; after that the app goes back to sleep.
+func Continue[M any](model M, now time.Time, anims ...Animation) app.UpdateResult[M] {
+	if Running(now, anims...) {
+		return app.WithCmd(model, app.RequestFrame())
+	}
+	return app.NoCmd(model)
+}
+
+// Cycle returns which of n frames a looping animation shows at now,
+// advancing one frame per period from start (frame 0 before it); e.g.
+// a spinner's frame index from the FrameMsg time.
+func Cycle(start, now time.Time, period time.Duration, n int) int {
+	elapsed := now.Sub(start)
+	if n <= 0 || period <= 0 || elapsed < 0 {
+		return 0
+	}
+	return int(elapsed/period) % n
+}
@@ -0,0 +1,171 @@
+package animate
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/node"
+)
+
+var t0 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestEasingEndpoints(t *testing.T) {
+	eases := map[string]Easing{
+		"Linear": Linear, "EaseIn": EaseIn, "EaseOut": EaseOut, "EaseInOut": EaseInOut,
+		"EaseInCubic": EaseInCubic, "EaseOutCubic": EaseOutCubic, "EaseInOutCubic": EaseInOutCubic,
+		"EaseOutBack": EaseOutBack, "EaseOutElastic": EaseOutElastic, "EaseOutBounce": EaseOutBounce,
+	}
+	for name, ease := range eases {
+		if got := ease(0); math.Abs(got) > 1e-9 {
+			t.Errorf("%s(0) = %v, want 0", name, got)
+		}
+		if got := ease(1); math.Abs(got-1) > 1e-9 {
+			t.Errorf("%s(1) = %v, want 1", name, got)
+		}
+	}
+	if got := EaseInOut(0.5); got != 0.5 {
+		t.Errorf("EaseInOut(0.5) = %v, want 0.5", got)
+	}
+	if EaseIn(0.25) >= 0.25 || EaseOut(0.25) <= 0.25 {
+		t.Error("EaseIn should lag and EaseOut lead linear progress")
+	}
+}
+
+func TestTweenAt(t *testing.T) {
+	tw := Float(10, 20, t0, time.Second, nil)
+	tests := []struct {
+		at   time.Duration
+		want float64
+		done bool
+	}{
+		{-time.Second, 10, false},
+		{0, 10, false},
+		{250 * time.Millisecond, 12.5, false},
+		{time.Second, 20, true},
+		{time.Hour, 20, true},
+	}
+	for _, tt := range tests {
+		now := t0.Add(tt.at)
+		if got := tw.At(now); got != tt.want {
+			t.Errorf("At(%v) = %v, want %v", tt.at, got, tt.want)
+		}
+		if got := tw.Done(now); got != tt.done {
+			t.Errorf("Done(%v) = %v, want %v", tt.at, got, tt.done)
+		}
+	}
+}
+
+func TestTweenZeroValue(t *testing.T) {
+	var tw Tween[float64]
+	if !tw.Done(t0) || tw.At(t0) != 0 {
+		t.Fatalf("zero Tween: Done = %v, At = %v", tw.Done(t0), tw.At(t0))
+	}
+}
+
+func TestTweenInt(t *testing.T) {
+	tw := Int(0, 10, t0, 100*time.Millisecond, EaseOut)
+	prev := 0
+	for ms := 0; ms <= 100; ms += 10 {
+		v := tw.At(t0.Add(time.Duration(ms) * time.Millisecond))
+		if v < prev {
+			t.Fatalf("At(%dms) = %d went back from %d", ms, v, prev)
+		}
+		prev = v
+	}
+	if prev != 10 {
+		t.Fatalf("ended at %d, want 10", prev)
+	}
+}
+
+func TestTweenRetarget(t *testing.T) {
+	tw := Float(0, 100, t0, time.Second, nil)
+	mid := t0.Add(500 * time.Millisecond)
+	tw = tw.Retarget(mid, 0)
+	if got := tw.At(mid); got != 50 {
+		t.Errorf("At retarget time = %v, want 50", got)
+	}
+	if got := tw.At(mid.Add(500 * time.Millisecond)); got != 25 {
+		t.Errorf("halfway back = %v, want 25", got)
+	}
+	if tw.Done(mid.Add(999*time.Millisecond)) || !tw.Done(mid.Add(time.Second)) {
+		t.Error("retargeted tween should run a full duration from the retarget")
+	}
+}
+
+func TestLerpColor(t *testing.T) {
+	from, to := node.RGB(0, 100, 255), node.RGB(100, 0, 255)
+	if got, want := LerpColor(from, to, 0.5), node.RGB(50, 50, 255); got != want {
+		t.Errorf("LerpColor halfway = %06x, want %06x", got, want)
+	}
+	if got := LerpColor(from, to, 1.5); got != node.RGB(150, 0, 255) {
+		r, g, b := got.RGBValues()
+		t.Errorf("overshoot = %d,%d,%d, want 150,0,255 (clamped)", r, g, b)
+	}
+	// Palette colors can't be blended; they switch halfway.
+	if got := LerpColor(node.Color(1), to, 0.4); got != node.Color(1) {
+		t.Errorf("palette at 0.4 = %v, want the start color", got)
+	}
+	if got := LerpColor(node.Color(1), to, 0.6); got != to {
+		t.Errorf("palette at 0.6 = %v, want the end color", got)
+	}
+}
+
+func TestCycle(t *testing.T) {
+	for _, tt := range []struct {
+		at   time.Duration
+		want int
+	}{
+		{0, 0}, {99 * time.Millisecond, 0}, {100 * time.Millisecond, 1},
+		{350 * time.Millisecond, 3}, {400 * time.Millisecond, 0}, {-50 * time.Millisecond, 0},
+	} {
+		if got := Cycle(t0, t0.Add(tt.at), 100*time.Millisecond, 4); got != tt.want {
+			t.Errorf("Cycle at %v = %d, want %d", tt.at, got, tt.want)
+		}
+	}
+}
+
+// fader is a model that fades its title in on a key press.
+type fader struct {
+	clock Clock
+	fade  Tween[node.Color]
+	now   time.Time
+}
+
+func (m fader) update(msg app.Msg) app.UpdateResult[fader] {
+	switch msg := msg.(type) {
+	case app.KeyMsg:
+		m.now = m.clock.Now()
+		m.fade = Color(node.RGB(0, 0, 0), node.RGB(200, 200, 200), m.now, 200*time.Millisecond, Linear)
+		return app.WithCmd(m, app.RequestFrame())
+	case app.FrameMsg:
+		m.now = msg.Time
+		return Continue(m, msg.Time, m.fade)
+	}
+	return app.NoCmd(m)
+}
+
+func TestFakeClockDrivesUpdate(t *testing.T) {
+	clock := NewFake(t0)
+	r := fader{clock: clock}.update(app.KeyMsg{})
+	var colors []node.Color
+	for len(r.Cmds) > 0 {
+		if len(colors) > 10 {
+			t.Fatal("animation never finished")
+		}
+		r = r.Model.update(clock.Frame(50 * time.Millisecond))
+		colors = append(colors, r.Model.fade.At(r.Model.now))
+	}
+	want := []node.Color{
+		node.RGB(50, 50, 50), node.RGB(100, 100, 100), node.RGB(150, 150, 150), node.RGB(200, 200, 200),
+	}
+	if len(colors) != len(want) {
+		t.Fatalf("got %d frames, want %d", len(colors), len(want))
+	}
+	for i := range want {
+		if colors[i] != want[i] {
+			t.Errorf("frame %d = %06x, want %06x", i, colors[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package animate
+
+import (
+	"sync"
+	"time"
+
+	"github.com/stukennedy/tooey/app"
+)
+
+// Clock tells the time tweens start from. Use System in apps and a
+// Fake in tests, so the same Update code can be stepped frame by
+// frame without sleeping.
+type Clock interface {
+	Now() time.Time
+}
+
+// System is the wall clock.
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// Fake is a Clock that only moves when told to. The zero value starts
+// at the zero time; it is safe for concurrent use.
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFake returns a Fake clock set to now.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the fake time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Advance moves the clock forward by d.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+// Frame advances the clock by d and returns the app.FrameMsg the
+// runtime would deliver at the new time, for feeding to Update.
+func (f *Fake) Frame(d time.Duration) app.FrameMsg {
+	f.Advance(d)
+	return app.FrameMsg{Time: f.Now()}
+}
@@ -0,0 +1,77 @@
+package animate
+
+import "math"
+
+// Easing maps linear progress t in [0, 1] to eased progress, with
+// Easing(0) == 0 and Easing(1) == 1. Back and elastic curves may
+// overshoot in between.
+type Easing func(t float64) float64
+
+// Linear is constant speed.
+func Linear(t float64) float64 { return t }
+
+// EaseIn starts slowly and accelerates (quadratic).
+func EaseIn(t float64) float64 { return t * t }
+
+// EaseOut starts fast and decelerates (quadratic).
+func EaseOut(t float64) float64 { return t * (2 - t) }
+
+// EaseInOut accelerates then decelerates (quadratic).
+func EaseInOut(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return -1 + (4-2*t)*t
+}
+
+// EaseInCubic is a steeper EaseIn.
+func EaseInCubic(t float64) float64 { return t * t * t }
+
+// EaseOutCubic is a steeper EaseOut; it suits scrolling.
+func EaseOutCubic(t float64) float64 {
+	t--
+	return t*t*t + 1
+}
+
+// EaseInOutCubic is a steeper EaseInOut.
+func EaseInOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	t = 2*t - 2
+	return t*t*t/2 + 1
+}
+
+// EaseOutBack overshoots the target slightly before settling.
+func EaseOutBack(t float64) float64 {
+	const c1 = 1.70158
+	const c3 = c1 + 1
+	t--
+	return 1 + c3*t*t*t + c1*t*t
+}
+
+// EaseOutElastic springs past the target and oscillates into place.
+func EaseOutElastic(t float64) float64 {
+	if t <= 0 || t >= 1 {
+		return t
+	}
+	return math.Pow(2, -10*t)*math.Sin((t*10-0.75)*(2*math.Pi/3)) + 1
+}
+
+// EaseOutBounce bounces off the target like a dropped ball.
+func EaseOutBounce(t float64) float64 {
+	const n1, d1 = 7.5625, 2.75
+	switch {
+	case t < 1/d1:
+		return n1 * t * t
+	case t < 2/d1:
+		t -= 1.5 / d1
+		return n1*t*t + 0.75
+	case t < 2.5/d1:
+		t -= 2.25 / d1
+		return n1*t*t + 0.9375
+	default:
+		t -= 2.625 / d1
+		return n1*t*t + 0.984375
+	}
+}
@@ -4,92 +4,121 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
+	"unicode/utf8"
 
 	"github.com/stukennedy/tooey/diff"
 	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/termcap"
 )
 
-// trueColor controls whether RGB colors are emitted as 24-bit SGR sequences
-// or downgraded to the nearest ANSI-256 palette entry. Detected from
-// COLORTERM at startup; override with SetTrueColor.
-var trueColor = detectTrueColor()
+// colorLevel is the color depth emitted: colors beyond it are
+// downgraded to the nearest color the terminal has. It defaults to
+// truecolor when COLORTERM says so and 256 colors otherwise; app.Run
+// applies full detection (see termcap) with SetDetectedColorLevel.
+var colorLevel = defaultColorLevel()
+
+// colorLevelSet records an explicit SetColorLevel or SetTrueColor,
+// which detection then leaves alone.
+var colorLevelSet bool
 
 func detectTrueColor() bool {
 	ct := os.Getenv("COLORTERM")
 	return ct == "truecolor" || ct == "24bit"
 }
 
+func defaultColorLevel() termcap.ColorLevel {
+	if detectTrueColor() {
+		return termcap.TrueColor
+	}
+	return termcap.ANSI256
+}
+
+// SetColorLevel sets the color depth to emit, overriding detection. At
+// termcap.Mono no colors are emitted at all; text styles still are.
+func SetColorLevel(level termcap.ColorLevel) {
+	colorLevel, colorLevelSet = level, true
+}
+
+// SetDetectedColorLevel sets the color depth found by capability
+// detection, unless SetColorLevel or SetTrueColor already chose one.
+func SetDetectedColorLevel(level termcap.ColorLevel) {
+	if !colorLevelSet {
+		colorLevel = level
+	}
+}
+
 // SetTrueColor overrides truecolor detection. When disabled, RGB colors
 // are downgraded to the nearest ANSI-256 palette entry.
-func SetTrueColor(enabled bool) { trueColor = enabled }
+func SetTrueColor(enabled bool) {
+	colorLevelSet = true
+	switch {
+	case enabled:
+		colorLevel = termcap.TrueColor
+	case colorLevel == termcap.TrueColor:
+		colorLevel = termcap.ANSI256
+	}
+}
 
 // Render writes the minimal ANSI escape sequences for the given changes.
+// The frame is assembled in memory and written with a single Write, so
+// the terminal never sees half a frame between reads. It assumes nothing
+// about the terminal's cursor or pen; use a Renderer to carry that state
+// between frames.
 func Render(w io.Writer, changes []diff.Change) {
-	var curFG, curBG node.Color
-	var curStyle node.StyleFlags
-	first := true
-
-	for _, ch := range changes {
-		// Move cursor (1-based)
-		fmt.Fprintf(w, "\x1b[%d;%dH", ch.Y+1, ch.X+1)
-
-		for _, c := range ch.Cells {
-			// The wide rune before a continuation cell already advanced
-			// the cursor past it.
-			if c.IsContinuation() {
-				continue
-			}
-			if first || c.FG != curFG || c.BG != curBG || c.Style != curStyle {
-				writeSGR(w, c.FG, c.BG, c.Style)
-				curFG = c.FG
-				curBG = c.BG
-				curStyle = c.Style
-				first = false
-			}
-			fmt.Fprintf(w, "%c", c.Rune)
+	var r Renderer
+	r.Render(w, changes)
+}
+
+// appendLink opens an OSC 8 hyperlink to url, or closes the open one
+// when url is empty. Control characters are dropped from the URL so it
+// can't terminate the sequence early.
+func appendLink(b []byte, url string) []byte {
+	b = append(b, "\x1b]8;;"...)
+	for _, r := range url {
+		if r >= 0x20 && r != 0x7f {
+			b = utf8.AppendRune(b, r)
 		}
 	}
-
-	// Reset at end
-	if !first {
-		fmt.Fprint(w, "\x1b[0m")
-	}
+	return append(b, "\x1b\\"...)
 }
 
-func writeSGR(w io.Writer, fg, bg node.Color, style node.StyleFlags) {
-	fmt.Fprint(w, "\x1b[0")
-	if style&node.Bold != 0 {
-		fmt.Fprint(w, ";1")
-	}
-	if style&node.Dim != 0 {
-		fmt.Fprint(w, ";2")
-	}
-	if style&node.Italic != 0 {
-		fmt.Fprint(w, ";3")
+// appendColor appends the SGR parameters for one color. base is 38 for
+// foreground, 48 for background and 58 for underline.
+func appendColor(b []byte, base int, c node.Color) []byte {
+	if c.IsDefault() || colorLevel == termcap.Mono {
+		return b
 	}
-	if style&node.Underline != 0 {
-		fmt.Fprint(w, ";4")
-	}
-	if style&node.Reverse != 0 {
-		fmt.Fprint(w, ";7")
-	}
-	writeColor(w, 38, fg)
-	writeColor(w, 48, bg)
-	fmt.Fprint(w, "m")
-}
-
-// writeColor emits the SGR parameters for one color. base is 38 for
-// foreground, 48 for background.
-func writeColor(w io.Writer, base int, c node.Color) {
-	if c.IsDefault() {
-		return
+	param := func(n int) {
+		b = append(b, ';')
+		b = strconv.AppendInt(b, int64(n), 10)
 	}
-	if c.IsRGB() && trueColor {
-		r, g, b := c.RGBValues()
-		fmt.Fprintf(w, ";%d;2;%d;%d;%d", base, r, g, b)
-		return
+	switch {
+	case c.IsRGB() && colorLevel == termcap.TrueColor:
+		r, g, bl := c.RGBValues()
+		param(base)
+		param(2)
+		param(int(r))
+		param(int(g))
+		param(int(bl))
+	case colorLevel == termcap.ANSI16:
+		n := int(c.Ansi16())
+		switch {
+		case base == 58: // underline colors only have the indexed form
+			param(58)
+			param(5)
+			param(n)
+		case n < 8: // 30-37, 40-47
+			param(base - 8 + n)
+		default: // bright: 90-97, 100-107
+			param(base + 52 + n - 8)
+		}
+	default:
+		param(base)
+		param(5)
+		param(int(c.Ansi256()))
 	}
-	fmt.Fprintf(w, ";%d;5;%d", base, c.Ansi256())
+	return b
 }
 
 // Terminal control sequences
@@ -114,6 +143,18 @@ func LeaveAltScreen(w io.Writer) {
 	fmt.Fprint(w, "\x1b[?1049l")
 }
 
+// BeginSync starts a synchronized update (DEC private mode 2026): the
+// terminal holds off repainting until EndSync, so a frame appears all at
+// once instead of tearing. Terminals without support ignore it.
+func BeginSync(w io.Writer) {
+	fmt.Fprint(w, "\x1b[?2026h")
+}
+
+// EndSync ends a synchronized update started with BeginSync.
+func EndSync(w io.Writer) {
+	fmt.Fprint(w, "\x1b[?2026l")
+}
+
 func MoveCursor(w io.Writer, x, y int) {
 	fmt.Fprintf(w, "\x1b[%d;%dH", y+1, x+1)
 }
@@ -127,11 +168,11 @@ func DisableFocusReporting(w io.Writer) {
 }
 
 func EnableMouseReporting(w io.Writer) {
-	fmt.Fprint(w, "\x1b[?1000h\x1b[?1006h") // basic + SGR mode
+	fmt.Fprint(w, "\x1b[?1000h\x1b[?1002h\x1b[?1006h") // basic + drag + SGR mode
 }
 
 func DisableMouseReporting(w io.Writer) {
-	fmt.Fprint(w, "\x1b[?1006l\x1b[?1000l")
+	fmt.Fprint(w, "\x1b[?1006l\x1b[?1002l\x1b[?1000l")
 }
 
 func EnableBracketedPaste(w io.Writer) {
@@ -60,3 +60,78 @@ func TestRenderNoChanges(t *testing.T) {
 		t.Fatalf("expected empty output, got %q", buf.String())
 	}
 }
+
+func TestRenderHyperlink(t *testing.T) {
+	link := "https://example.com/a"
+	changes := []diff.Change{
+		{X: 0, Y: 0, Cells: []cell.Cell{
+			{Rune: 'x'},
+			{Rune: 'g', Link: link},
+			{Rune: 'o', Link: link},
+			{Rune: '!'},
+		}},
+		{X: 0, Y: 1, Cells: []cell.Cell{{Rune: 'z', Link: "https://evil\x1b\\x"}}},
+	}
+	var buf bytes.Buffer
+	Render(&buf, changes)
+	out := buf.String()
+	open, closeLink := "\x1b]8;;"+link+"\x1b\\", "\x1b]8;;\x1b\\"
+	if !strings.Contains(out, open+"go"+closeLink+"!") {
+		t.Fatalf("link run not wrapped in OSC 8, got: %q", out)
+	}
+	// Control characters are stripped, and the last link is closed.
+	if !strings.Contains(out, "\x1b]8;;https://evil\\x\x1b\\z"+closeLink) {
+		t.Fatalf("unsanitized or unclosed link, got: %q", out)
+	}
+}
+
+func TestRenderExtendedStyles(t *testing.T) {
+	changes := []diff.Change{
+		{X: 0, Y: 0, Cells: []cell.Cell{
+			{Rune: 'a', Style: node.Strikethrough | node.Overline | node.Blink | node.Hidden},
+			{Rune: 'b', Style: node.Underline | node.CurlyUnderline, UL: node.RGB(255, 0, 0)},
+			{Rune: 'c', Style: node.Underline | node.CurlyUnderline, UL: 1}, // new underline color, SGR delta
+		}},
+	}
+	SetTrueColor(true)
+	defer SetTrueColor(detectTrueColor())
+	var buf bytes.Buffer
+	Render(&buf, changes)
+	out := buf.String()
+	for _, want := range []string{
+		"\x1b[0;5;8;9;53ma",
+		"\x1b[0;4:3;58;2;255;0;0mb",
+		"\x1b[58;5;1mc", // only the underline color changes
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in %q", want, out)
+		}
+	}
+}
+
+// countingWriter counts Write calls.
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestRenderSingleWrite(t *testing.T) {
+	changes := []diff.Change{
+		{X: 0, Y: 0, Cells: []cell.Cell{{Rune: 'a', FG: 1}, {Rune: 'b', FG: 2}}},
+		{X: 5, Y: 2, Cells: []cell.Cell{{Rune: '世'}, {Rune: 0}, {Rune: 'c', Style: node.Bold}}},
+	}
+	var w countingWriter
+	Render(&w, changes)
+	if w.writes != 1 {
+		t.Fatalf("expected one write per frame, got %d", w.writes)
+	}
+	want := "\x1b[1;1H\x1b[0;38;5;1ma\x1b[38;5;2mb\x1b[3;6H\x1b[0m世\x1b[1mc\x1b[0m"
+	if w.String() != want {
+		t.Fatalf("got  %q\nwant %q", w.String(), want)
+	}
+}
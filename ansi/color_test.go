@@ -8,6 +8,7 @@ import (
 	"github.com/stukennedy/tooey/cell"
 	"github.com/stukennedy/tooey/diff"
 	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/termcap"
 )
 
 func renderCells(cells ...cell.Cell) string {
@@ -89,3 +90,51 @@ func TestRGBToAnsi256Grayscale(t *testing.T) {
 		t.Fatalf("white should map to cube 231, got %d", node.RGB(255, 255, 255).Ansi256())
 	}
 }
+
+func TestRender16Colors(t *testing.T) {
+	SetColorLevel(termcap.ANSI16)
+	defer SetColorLevel(defaultColorLevel())
+
+	tests := []struct {
+		cell cell.Cell
+		want string
+	}{
+		{cell.Cell{Rune: 'A', FG: 1, BG: 4}, ";31;44m"},
+		{cell.Cell{Rune: 'A', FG: 9, BG: 12}, ";91;104m"},
+		{cell.Cell{Rune: 'A', FG: node.Ansi(0)}, ";30m"},
+		{cell.Cell{Rune: 'A', FG: node.RGB(250, 10, 10)}, ";91m"}, // bright red
+		{cell.Cell{Rune: 'A', FG: 28}, ";32m"},                    // green cube entry
+		{cell.Cell{Rune: 'A', FG: 255}, ";37m"},                   // light gray
+		{cell.Cell{Rune: 'A', Style: node.Underline, UL: node.RGB(0, 0, 255)}, ";4;58;5;4m"},
+	}
+	for _, tt := range tests {
+		if out := renderCells(tt.cell); !strings.Contains(out, tt.want) {
+			t.Errorf("%+v: want %q in %q", tt.cell, tt.want, out)
+		}
+	}
+}
+
+func TestDetectedColorLevelKeepsOverride(t *testing.T) {
+	defer func() { colorLevel, colorLevelSet = defaultColorLevel(), false }()
+
+	colorLevelSet = false
+	SetDetectedColorLevel(termcap.ANSI16)
+	if colorLevel != termcap.ANSI16 {
+		t.Fatalf("detection not applied: level %v", colorLevel)
+	}
+	SetTrueColor(true)
+	SetDetectedColorLevel(termcap.ANSI16)
+	if colorLevel != termcap.TrueColor {
+		t.Fatalf("detection replaced SetTrueColor: level %v", colorLevel)
+	}
+}
+
+func TestRenderMono(t *testing.T) {
+	SetColorLevel(termcap.Mono)
+	defer SetColorLevel(defaultColorLevel())
+
+	out := renderCells(cell.Cell{Rune: 'A', FG: node.RGB(255, 0, 0), BG: 4, Style: node.Bold})
+	if !strings.Contains(out, "\x1b[0;1mA") {
+		t.Fatalf("expected bold without colors, got %q", out)
+	}
+}
@@ -0,0 +1,492 @@
+package ansi
+
+import (
+	"io"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/stukennedy/tooey/cell"
+	"github.com/stukennedy/tooey/diff"
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/termcap"
+	"github.com/stukennedy/tooey/textwidth"
+)
+
+// Renderer encodes frames of changes into as few bytes as it can. It
+// remembers the terminal's cursor position and pen (colors and styles)
+// between runs and frames, so it moves the cursor relatively when that
+// is shorter, emits only the SGR attributes that differ, and erases
+// runs of blank cells (ECH, EL) instead of writing them out.
+//
+// A Renderer assumes it is the only thing moving the cursor or setting
+// attributes; call Invalidate after writing anything else. Its output
+// buffer is reused from frame to frame. The zero value is ready to use
+// for a screen of unknown size.
+type Renderer struct {
+	// Sync wraps each frame in a synchronized update (see BeginSync).
+	Sync bool
+
+	width  int
+	height int
+	buf    []byte
+	clear  bool // clear the screen at the start of the next frame
+
+	// What the terminal is known to be showing. The cursor is unknown
+	// after writing the last column, which leaves a pending wrap.
+	x, y        int
+	cursorKnown bool
+	pen         pen
+	penKnown    bool
+}
+
+// pen is the set of SGR attributes cells are written with.
+type pen struct {
+	fg, bg, ul node.Color
+	style      node.StyleFlags
+}
+
+func penOf(c cell.Cell) pen {
+	return pen{fg: c.FG, bg: c.BG, ul: c.UL, style: c.Style}
+}
+
+// NewRenderer returns a Renderer for a width × height screen.
+func NewRenderer(width, height int) *Renderer {
+	r := &Renderer{}
+	r.Resize(width, height)
+	return r
+}
+
+// Resize sets the screen size and forgets the cursor position. A width
+// of 0 means unknown: every run then starts with an absolute move and
+// EL is never used.
+func (r *Renderer) Resize(width, height int) {
+	r.width, r.height = width, height
+	r.Invalidate()
+}
+
+// Invalidate forgets the cursor position and pen, e.g. after other
+// output was written to the terminal.
+func (r *Renderer) Invalidate() {
+	r.cursorKnown = false
+	r.penKnown = false
+}
+
+// Clear makes the next frame start by clearing the screen, e.g. after
+// a resize exposes stale content.
+func (r *Renderer) Clear() { r.clear = true }
+
+// Render writes the escape sequences for changes with a single Write.
+// Nothing is written when there are no changes and no pending Clear.
+func (r *Renderer) Render(w io.Writer, changes []diff.Change) {
+	r.RenderScroll(w, diff.Scroll{}, changes)
+}
+
+// RenderScroll is like Render, but first scrolls the screen as s says
+// (see diff.Differ.DiffScroll).
+func (r *Renderer) RenderScroll(w io.Writer, s diff.Scroll, changes []diff.Change) {
+	if len(changes) == 0 && s.N == 0 && !r.clear {
+		return
+	}
+	b := r.buf[:0]
+	if r.Sync {
+		b = append(b, "\x1b[?2026h"...)
+	}
+	if r.clear {
+		b = append(b, "\x1b[2J"...)
+		r.clear = false
+	}
+	if s.N != 0 {
+		b = r.appendScroll(b, s)
+	}
+	b = r.appendChanges(b, changes)
+	if r.Sync {
+		b = append(b, "\x1b[?2026l"...)
+	}
+	r.buf = b
+	w.Write(b)
+}
+
+// appendChanges appends the escape sequences for changes to b.
+func (r *Renderer) appendChanges(b []byte, changes []diff.Change) []byte {
+	var link string
+	for _, ch := range changes {
+		b = r.appendMoveTo(b, ch.X, ch.Y)
+		x, cur := ch.X, ch.X // next cell, and where the cursor is
+		wide := false
+		for i := 0; i < len(ch.Cells); i++ {
+			c := ch.Cells[i]
+			// The wide rune before a continuation cell already advanced
+			// the cursor past it.
+			if c.IsContinuation() {
+				x++
+				continue
+			}
+			if n := blankRun(ch.Cells[i:]); n > 0 && link == "" {
+				last := i+n == len(ch.Cells)
+				if e, ok := r.appendErase(b, penOf(c), x, n, last); ok {
+					b = e
+					// EL and a final ECH leave the cursor where it was;
+					// otherwise CUF moved it past the erased cells.
+					i += n - 1
+					x += n
+					if !last {
+						cur = x
+					}
+					continue
+				}
+			}
+			b = r.appendPen(b, penOf(c))
+			if c.Link != link {
+				b = appendLink(b, c.Link)
+				link = c.Link
+			}
+			b = utf8.AppendRune(b, c.Rune)
+			x++
+			cur = x
+			wide = wide || textwidth.Rune(c.Rune) == 2
+		}
+		// Past the last column the terminal holds a pending wrap, and
+		// with no known width we can't tell where that is. Terminals
+		// may also disagree on a wide rune's width. Either way the next
+		// move must be absolute.
+		r.x, r.y = cur, ch.Y
+		r.cursorKnown = r.width > 0 && cur < r.width && !wide
+	}
+
+	// Leave the terminal with a plain pen and no open link, so anything
+	// else written (and erases, which fill with the pen's background)
+	// is unaffected.
+	if link != "" {
+		b = appendLink(b, "")
+	}
+	if r.penKnown && r.pen != (pen{}) {
+		b = append(b, "\x1b[0m"...)
+		r.pen = pen{}
+	}
+	return b
+}
+
+// appendScroll scrolls rows s.Top through s.Bottom: it sets the
+// scroll region (DECSTBM) unless that is the whole screen, scrolls it
+// (SU, SD) and resets the region.
+func (r *Renderer) appendScroll(b []byte, s diff.Scroll) []byte {
+	// Rows scrolled in take the pen's background.
+	if !r.penKnown || r.pen != (pen{}) {
+		b = append(b, "\x1b[0m"...)
+		r.pen, r.penKnown = pen{}, true
+	}
+	region := s.Top != 0 || s.Bottom != r.height-1
+	if region {
+		b = append(b, "\x1b["...)
+		b = strconv.AppendInt(b, int64(s.Top+1), 10)
+		b = append(b, ';')
+		b = strconv.AppendInt(b, int64(s.Bottom+1), 10)
+		b = append(b, 'r')
+	}
+	if s.N > 0 {
+		b = appendCSI(b, s.N, 'S')
+	} else {
+		b = appendCSI(b, -s.N, 'T')
+	}
+	if region {
+		// Setting and resetting the region both home the cursor.
+		b = append(b, "\x1b[r"...)
+		r.x, r.y = 0, 0
+		r.cursorKnown = r.width > 0
+	}
+	return b
+}
+
+// eraseVisible are the styles that show on a blank cell, which an erase
+// would lose.
+const eraseVisible = node.Underline | node.DoubleUnderline | node.CurlyUnderline |
+	node.DottedUnderline | node.DashedUnderline | node.Reverse | node.Strikethrough | node.Overline
+
+// blankRun returns how many identical cells start cells if they are
+// blanks an erase reproduces exactly, or 0.
+func blankRun(cells []cell.Cell) int {
+	first := cells[0]
+	if first.Rune != ' ' || !first.BG.IsDefault() || first.Link != "" || first.Style&eraseVisible != 0 {
+		return 0
+	}
+	n := 1
+	for n < len(cells) && cells[n] == first {
+		n++
+	}
+	return n
+}
+
+// appendErase erases n blank cells at column x: with EL when they reach
+// the right edge, and otherwise ECH, followed by a move past them when
+// more cells follow. It reports false when writing the spaces out
+// would be as short.
+func (r *Renderer) appendErase(b []byte, p pen, x, n int, last bool) ([]byte, bool) {
+	toEdge := last && r.width > 0 && x+n == r.width
+	cost := csiLen(n)
+	switch {
+	case toEdge:
+		cost = len("\x1b[K")
+	case !last:
+		cost *= 2
+	}
+	if cost >= n {
+		return b, false
+	}
+	// Erased cells take the pen's background, so set it first.
+	b = r.appendPen(b, p)
+	switch {
+	case toEdge:
+		return append(b, "\x1b[K"...), true
+	case last:
+		return appendCSI(b, n, 'X'), true
+	default:
+		return appendCSI(appendCSI(b, n, 'X'), n, 'C'), true
+	}
+}
+
+// appendCSI appends a CSI sequence with one numeric parameter.
+func appendCSI(b []byte, n int, final byte) []byte {
+	b = append(b, "\x1b["...)
+	b = strconv.AppendInt(b, int64(n), 10)
+	return append(b, final)
+}
+
+// csiLen is the length of appendCSI's output for n.
+func csiLen(n int) int {
+	l := 4
+	for ; n >= 10; n /= 10 {
+		l++
+	}
+	return l
+}
+
+// appendMoveTo moves the cursor to the 0-based (x, y), relatively when
+// the cursor is known and that is shorter than an absolute move.
+func (r *Renderer) appendMoveTo(b []byte, x, y int) []byte {
+	if !r.cursorKnown {
+		return appendMove(b, x, y)
+	}
+	dx, dy := x-r.x, y-r.y
+	if dx == 0 && dy == 0 {
+		return b
+	}
+
+	// Relative: CUU/CUD, then CUF/CUB or a carriage return and CUF.
+	vert := 0
+	switch {
+	case dy > 0:
+		vert = csiLen(dy)
+	case dy < 0:
+		vert = csiLen(-dy)
+	}
+	horiz, cr := 0, false
+	switch {
+	case dx > 0:
+		horiz = csiLen(dx)
+	case dx < 0:
+		horiz = csiLen(-dx)
+		if l := 1 + cufLen(x); l < horiz {
+			horiz, cr = l, true
+		}
+	}
+	// CR-LF: down to the start of the line with line feeds, then across.
+	crlf := -1
+	if dy > 0 {
+		crlf = 1 + dy + cufLen(x)
+	}
+	abs := moveLen(x, y)
+
+	switch {
+	case crlf >= 0 && crlf <= vert+horiz && crlf < abs:
+		b = append(b, '\r')
+		for range dy {
+			b = append(b, '\n')
+		}
+		if x > 0 {
+			b = appendCSI(b, x, 'C')
+		}
+	case vert+horiz < abs:
+		switch {
+		case dy > 0:
+			b = appendCSI(b, dy, 'B')
+		case dy < 0:
+			b = appendCSI(b, -dy, 'A')
+		}
+		switch {
+		case cr:
+			b = append(b, '\r')
+			if x > 0 {
+				b = appendCSI(b, x, 'C')
+			}
+		case dx > 0:
+			b = appendCSI(b, dx, 'C')
+		case dx < 0:
+			b = appendCSI(b, -dx, 'D')
+		}
+	default:
+		b = appendMove(b, x, y)
+	}
+	return b
+}
+
+// cufLen is the cost of moving right from column 0 to x.
+func cufLen(x int) int {
+	if x == 0 {
+		return 0
+	}
+	return csiLen(x)
+}
+
+// appendMove appends an absolute cursor move to the 0-based (x, y).
+func appendMove(b []byte, x, y int) []byte {
+	b = append(b, "\x1b["...)
+	b = strconv.AppendInt(b, int64(y+1), 10)
+	b = append(b, ';')
+	b = strconv.AppendInt(b, int64(x+1), 10)
+	return append(b, 'H')
+}
+
+// moveLen is the length of appendMove's output for (x, y).
+func moveLen(x, y int) int {
+	return csiLen(y+1) + csiLen(x+1) - 2
+}
+
+// appendPen switches the pen to p with whichever is shorter: a reset
+// followed by p's attributes, or just the attributes that differ.
+func (r *Renderer) appendPen(b []byte, p pen) []byte {
+	if colorLevel == termcap.Mono {
+		p.fg, p.bg, p.ul = 0, 0, 0
+	}
+	if r.penKnown && p == r.pen {
+		return b
+	}
+	start := len(b)
+	b = appendSGR(b, p)
+	if r.penKnown {
+		// Build the delta after the full form and keep it if shorter.
+		full := b[start:]
+		d := appendSGRDelta(b, r.pen, p)
+		delta := d[len(b):]
+		if len(delta) < len(full) {
+			b = append(b[:start], delta...)
+		}
+	}
+	r.pen, r.penKnown = p, true
+	return b
+}
+
+// sgrFlags maps style flags to their SGR parameters and the parameter
+// that turns them off. Underline is handled separately because of its
+// variants; bold and dim share their off parameter.
+var sgrFlags = []struct {
+	flag    node.StyleFlags
+	on, off string
+}{
+	{node.Bold, ";1", ";22"},
+	{node.Dim, ";2", ";22"},
+	{node.Italic, ";3", ";23"},
+	{node.Blink, ";5", ";25"},
+	{node.Reverse, ";7", ";27"},
+	{node.Hidden, ";8", ";28"},
+	{node.Strikethrough, ";9", ";29"},
+	{node.Overline, ";53", ";55"},
+}
+
+// underlineParams maps underline variants to their SGR 4 sub-parameter,
+// in order of precedence.
+var underlineParams = []struct {
+	flag  node.StyleFlags
+	param string
+}{
+	{node.DoubleUnderline, ";4:2"},
+	{node.CurlyUnderline, ";4:3"},
+	{node.DottedUnderline, ";4:4"},
+	{node.DashedUnderline, ";4:5"},
+	{node.Underline, ";4"},
+}
+
+// underlineParam returns the SGR parameter for style's underline, or ""
+// when it has none.
+func underlineParam(style node.StyleFlags) string {
+	for _, u := range underlineParams {
+		if style&u.flag != 0 {
+			return u.param
+		}
+	}
+	return ""
+}
+
+// appendSGR appends a reset followed by all of p's attributes.
+func appendSGR(b []byte, p pen) []byte {
+	b = append(b, "\x1b[0"...)
+	for _, f := range sgrFlags {
+		if p.style&f.flag != 0 {
+			b = append(b, f.on...)
+		}
+	}
+	b = append(b, underlineParam(p.style)...)
+	b = appendColor(b, 38, p.fg)
+	b = appendColor(b, 48, p.bg)
+	b = appendColor(b, 58, p.ul)
+	return append(b, 'm')
+}
+
+// appendSGRDelta appends an SGR sequence changing the pen from old to p
+// without a reset.
+func appendSGRDelta(b []byte, old, p pen) []byte {
+	start := len(b)
+	b = append(b, "\x1b["...)
+	params := len(b)
+	// Bold and dim go off together, so one lost means re-adding the
+	// other.
+	const intensity = node.Bold | node.Dim
+	readd := old.style&intensity&^p.style != 0
+	off := false
+	for _, f := range sgrFlags {
+		was, is := old.style&f.flag != 0, p.style&f.flag != 0
+		switch {
+		case was && !is && f.flag&intensity != 0:
+			if !off {
+				b = append(b, f.off...)
+				off = true
+			}
+		case was && !is:
+			b = append(b, f.off...)
+		}
+	}
+	for _, f := range sgrFlags {
+		was, is := old.style&f.flag != 0, p.style&f.flag != 0
+		if is && (!was || readd && f.flag&intensity != 0) {
+			b = append(b, f.on...)
+		}
+	}
+	if u, v := underlineParam(old.style), underlineParam(p.style); u != v {
+		if v == "" {
+			v = ";24"
+		}
+		b = append(b, v...)
+	}
+	b = appendColorDelta(b, 38, old.fg, p.fg)
+	b = appendColorDelta(b, 48, old.bg, p.bg)
+	b = appendColorDelta(b, 58, old.ul, p.ul)
+	if len(b) == params {
+		return b[:start]
+	}
+	// Drop the separator before the first parameter.
+	copy(b[params:], b[params+1:])
+	b = b[:len(b)-1]
+	return append(b, 'm')
+}
+
+// appendColorDelta appends the SGR parameters changing a color from old
+// to c: the color itself, or the default (39, 49, 59).
+func appendColorDelta(b []byte, base int, old, c node.Color) []byte {
+	if old == c {
+		return b
+	}
+	if c.IsDefault() {
+		b = append(b, ';')
+		return strconv.AppendInt(b, int64(base+1), 10)
+	}
+	return appendColor(b, base, c)
+}
@@ -0,0 +1,461 @@
+package ansi
+
+import (
+	"bytes"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stukennedy/tooey/cell"
+	"github.com/stukennedy/tooey/diff"
+	"github.com/stukennedy/tooey/node"
+)
+
+func cells(s string, c cell.Cell) []cell.Cell {
+	var out []cell.Cell
+	for _, r := range s {
+		c.Rune = r
+		out = append(out, c)
+	}
+	return out
+}
+
+func renderFrame(r *Renderer, changes ...diff.Change) string {
+	var buf bytes.Buffer
+	r.Render(&buf, changes)
+	return buf.String()
+}
+
+func TestRendererRelativeMoves(t *testing.T) {
+	r := NewRenderer(80, 24)
+	out := renderFrame(r,
+		diff.Change{X: 10, Y: 15, Cells: cells("ab", cell.Cell{})},
+		diff.Change{X: 20, Y: 15, Cells: cells("c", cell.Cell{})}, // same row: CUF
+		diff.Change{X: 0, Y: 16, Cells: cells("d", cell.Cell{})},  // next line: CR-LF
+		diff.Change{X: 9, Y: 18, Cells: cells("e", cell.Cell{})},  // CR, LFs, CUF
+		diff.Change{X: 75, Y: 18, Cells: cells("f", cell.Cell{})},
+		diff.Change{X: 0, Y: 10, Cells: cells("g", cell.Cell{})},  // CUU and CR
+		diff.Change{X: 60, Y: 20, Cells: cells("h", cell.Cell{})}, // far: absolute
+	)
+	want := "\x1b[16;11H\x1b[0mab\x1b[8Cc\r\nd\r\n\n\x1b[9Ce\x1b[65Cf\x1b[8A\rg\x1b[21;61Hh"
+	if out != want {
+		t.Fatalf("got  %q\nwant %q", out, want)
+	}
+
+	// The cursor position carries over to the next frame.
+	if out := renderFrame(r, diff.Change{X: 61, Y: 20, Cells: cells("i", cell.Cell{})}); out != "i" {
+		t.Fatalf("second frame = %q", out)
+	}
+}
+
+func TestRendererAbsoluteAfterEdge(t *testing.T) {
+	r := NewRenderer(10, 4)
+	out := renderFrame(r,
+		diff.Change{X: 8, Y: 0, Cells: cells("ab", cell.Cell{})}, // pending wrap
+		diff.Change{X: 0, Y: 1, Cells: cells("c", cell.Cell{})},
+		diff.Change{X: 0, Y: 2, Cells: []cell.Cell{{Rune: '世'}, {Rune: 0}}}, // wide rune
+		diff.Change{X: 5, Y: 2, Cells: cells("d", cell.Cell{})},
+	)
+	want := "\x1b[1;9H\x1b[0mab\x1b[2;1Hc\r\n世\x1b[3;6Hd"
+	if out != want {
+		t.Fatalf("got  %q\nwant %q", out, want)
+	}
+}
+
+func TestRendererSGRDelta(t *testing.T) {
+	r := NewRenderer(80, 24)
+	out := renderFrame(r, diff.Change{X: 0, Y: 0, Cells: []cell.Cell{
+		{Rune: 'a', FG: 1, Style: node.Bold | node.Dim | node.Italic},
+		{Rune: 'b', FG: 1, Style: node.Dim | node.Italic},                  // bold off re-adds dim
+		{Rune: 'c', FG: 1, BG: 4, Style: node.Dim | node.Italic},           // background only
+		{Rune: 'd', BG: 4, Style: node.Dim | node.Italic | node.Underline}, // default fg, underline on
+		{Rune: 'e', BG: 4, Style: node.Dim | node.Italic},                  // underline off
+		{Rune: 'f'}, // everything off: a reset is shorter
+	}})
+	want := "\x1b[1;1H\x1b[0;1;2;3;38;5;1ma\x1b[22;2mb\x1b[48;5;4mc\x1b[4;39md\x1b[24me\x1b[0mf"
+	if out != want {
+		t.Fatalf("got  %q\nwant %q", out, want)
+	}
+}
+
+func TestRendererEndsWithReset(t *testing.T) {
+	r := NewRenderer(80, 24)
+	out := renderFrame(r, diff.Change{X: 0, Y: 0, Cells: cells("x", cell.Cell{Style: node.Bold})})
+	if !strings.HasSuffix(out, "x\x1b[0m") {
+		t.Fatalf("frame not reset: %q", out)
+	}
+	// The next frame knows the pen is plain.
+	if out := renderFrame(r, diff.Change{X: 1, Y: 0, Cells: cells("y", cell.Cell{})}); out != "y" {
+		t.Fatalf("second frame = %q", out)
+	}
+}
+
+func TestRendererErase(t *testing.T) {
+	r := NewRenderer(40, 5)
+	row := append(cells("ab", cell.Cell{}), cells(strings.Repeat(" ", 12), cell.Cell{})...)
+	row = append(row, cells("cd", cell.Cell{})...)
+	row = append(row, cells(strings.Repeat(" ", 24), cell.Cell{})...)
+	out := renderFrame(r,
+		diff.Change{X: 0, Y: 0, Cells: row},
+		diff.Change{X: 0, Y: 1, Cells: cells("ef    ", cell.Cell{})},                        // short: spaces
+		diff.Change{X: 0, Y: 2, Cells: cells("g        ", cell.Cell{})},                     // ECH, cursor stays
+		diff.Change{X: 0, Y: 3, Cells: cells("          ", cell.Cell{BG: 2})},               // colored: spaces
+		diff.Change{X: 0, Y: 4, Cells: cells("          ", cell.Cell{Style: node.Reverse})}, // visible: spaces
+	)
+	want := "\x1b[1;1H\x1b[0mab\x1b[12X\x1b[12Ccd\x1b[K" +
+		"\r\nef    " +
+		"\r\ng\x1b[8X" +
+		"\r\n\x1b[48;5;2m          " +
+		"\r\n\x1b[0;7m          \x1b[0m"
+	if out != want {
+		t.Fatalf("got  %q\nwant %q", out, want)
+	}
+}
+
+func TestRendererAdjacentErases(t *testing.T) {
+	r := NewRenderer(40, 5)
+	s := newScreen(40, 5)
+	row := append(cells("a", cell.Cell{}), cells(strings.Repeat(" ", 9), cell.Cell{FG: 1})...)
+	row = append(row, cells(strings.Repeat(" ", 5), cell.Cell{FG: 2})...)
+	out := renderFrame(r, diff.Change{X: 2, Y: 1, Cells: row})
+	s.apply(t, out)
+	// The second ECH leaves the cursor after the first erase, at x=12.
+	if want := "\x1b[2;3H\x1b[0ma\x1b[38;5;1m\x1b[9X\x1b[9C\x1b[38;5;2m\x1b[5X\x1b[0m"; out != want {
+		t.Fatalf("got  %q\nwant %q", out, want)
+	}
+	out = renderFrame(r, diff.Change{X: 12, Y: 2, Cells: cells("b", cell.Cell{})})
+	s.apply(t, out)
+	if out != "\x1b[1Bb" {
+		t.Fatalf("second frame = %q", out)
+	}
+	if got := s.cells[2*40+12].r; got != 'b' {
+		t.Fatalf("b landed elsewhere: cell under it is %q", got)
+	}
+}
+
+func TestRendererSyncAndClear(t *testing.T) {
+	r := NewRenderer(10, 2)
+	r.Sync = true
+	if out := renderFrame(r); out != "" {
+		t.Fatalf("empty frame wrote %q", out)
+	}
+	r.Clear()
+	if out := renderFrame(r); out != "\x1b[?2026h\x1b[2J\x1b[?2026l" {
+		t.Fatalf("clear frame = %q", out)
+	}
+}
+
+// screen is a minimal terminal: it applies the cursor movement, erase
+// and text output a Renderer emits, and tracks the pen's colors (erased
+// cells take its background). Other SGR attributes and OSC sequences
+// are ignored.
+type screen struct {
+	w, h        int
+	cells       []screenCell
+	x, y        int
+	top, bottom int // scroll region
+	fg, bg      node.Color
+}
+
+type screenCell struct {
+	r      rune
+	fg, bg node.Color
+}
+
+func newScreen(w, h int) *screen {
+	s := &screen{w: w, h: h, cells: make([]screenCell, w*h), bottom: h - 1}
+	for i := range s.cells {
+		s.cells[i] = screenCell{r: ' '}
+	}
+	return s
+}
+
+// sgr applies an SGR sequence's parameters to the pen.
+func (s *screen) sgr(params []string) {
+	for i := 0; i < len(params); i++ {
+		n, _ := strconv.Atoi(params[i])
+		switch n {
+		case 0:
+			s.fg, s.bg = 0, 0
+		case 39:
+			s.fg = 0
+		case 49:
+			s.bg = 0
+		case 38, 48, 58:
+			var c node.Color
+			if params[i+1] == "5" {
+				v, _ := strconv.Atoi(params[i+2])
+				c = node.Ansi(uint8(v))
+				i += 2
+			} else { // 2;r;g;b
+				r, _ := strconv.Atoi(params[i+2])
+				g, _ := strconv.Atoi(params[i+3])
+				b, _ := strconv.Atoi(params[i+4])
+				c = node.RGB(uint8(r), uint8(g), uint8(b))
+				i += 4
+			}
+			switch n {
+			case 38:
+				s.fg = c
+			case 48:
+				s.bg = c
+			}
+		}
+	}
+}
+
+// blank is what an erase leaves: a space in the pen's background.
+func (s *screen) blank() screenCell {
+	return screenCell{r: ' ', bg: s.bg}
+}
+
+func (s *screen) apply(t *testing.T, out string) {
+	for i := 0; i < len(out); {
+		switch out[i] {
+		case '\r':
+			s.x = 0
+			i++
+		case '\n':
+			s.y++
+			i++
+		case '\x1b':
+			if out[i+1] == ']' { // OSC, up to ST
+				i += strings.Index(out[i:], "\x1b\\") + 2
+				continue
+			}
+			j := i + 2
+			for out[j] < 0x40 {
+				j++
+			}
+			params := strings.Split(out[i+2:j], ";")
+			n, _ := strconv.Atoi(params[0])
+			switch out[j] {
+			case 'H':
+				s.y = n - 1
+				s.x, _ = strconv.Atoi(params[1])
+				s.x--
+			case 'A':
+				s.y -= n
+			case 'B':
+				s.y += n
+			case 'C':
+				s.x += n
+			case 'D':
+				s.x -= n
+			case 'X':
+				for k := range n {
+					s.cells[s.y*s.w+s.x+k] = s.blank()
+				}
+			case 'K':
+				for k := s.x; k < s.w; k++ {
+					s.cells[s.y*s.w+k] = s.blank()
+				}
+			case 'r':
+				s.top, s.bottom = 0, s.h-1
+				if len(params) == 2 {
+					s.top = n - 1
+					s.bottom, _ = strconv.Atoi(params[1])
+					s.bottom--
+				}
+				s.x, s.y = 0, 0
+			case 'S':
+				s.scroll(n)
+			case 'T':
+				s.scroll(-n)
+			case 'm':
+				s.sgr(params)
+			default:
+				t.Fatalf("unexpected sequence %q", out[i:j+1])
+			}
+			i = j + 1
+		default:
+			r := []rune(out[i:])[0]
+			if s.x < 0 || s.x >= s.w || s.y < 0 || s.y >= s.h {
+				t.Fatalf("write outside the screen at %d,%d", s.x, s.y)
+			}
+			s.cells[s.y*s.w+s.x] = screenCell{r: r, fg: s.fg, bg: s.bg}
+			s.x++
+			i += len(string(r))
+		}
+	}
+}
+
+// scroll moves the scroll region's rows up by n, or down by -n.
+func (s *screen) scroll(n int) {
+	old := append([]screenCell(nil), s.cells...)
+	for y := s.top; y <= s.bottom; y++ {
+		for x := range s.w {
+			s.cells[y*s.w+x] = s.blank()
+			if src := y + n; src >= s.top && src <= s.bottom {
+				s.cells[y*s.w+x] = old[src*s.w+x]
+			}
+		}
+	}
+}
+
+// matches reports whether the screen shows b. A space's foreground
+// isn't visible (and erases don't set one), so it isn't compared.
+func (s *screen) matches(b *cell.Buffer) bool {
+	for i, c := range b.Cells {
+		want := screenCell{r: c.Rune, fg: screenColor(c.FG), bg: screenColor(c.BG)}
+		got := s.cells[i]
+		if c.Rune == ' ' {
+			want.fg, got.fg = 0, 0
+		}
+		if got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// screenColor is c as the screen records it from SGR.
+func screenColor(c node.Color) node.Color {
+	if c.IsDefault() || c.IsRGB() {
+		return c
+	}
+	return node.Ansi(c.Ansi256())
+}
+
+func TestRendererReproducesFrames(t *testing.T) {
+	const w, h = 60, 8
+	rng := rand.New(rand.NewSource(1))
+	r := NewRenderer(w, h)
+	s := newScreen(w, h)
+	prev := cell.NewBuffer(w, h)
+	for frame := range 200 {
+		next := cell.NewBuffer(w, h)
+		copy(next.Cells, prev.Cells)
+		for range rng.Intn(40) {
+			x, y := rng.Intn(w), rng.Intn(h)
+			n := 1 + rng.Intn(w-x)
+			c := cell.Cell{Rune: 'a' + rune(rng.Intn(3)), FG: node.Color(rng.Intn(3)), Style: node.StyleFlags(rng.Intn(4))}
+			if rng.Intn(2) == 0 {
+				// Blanks in different pens make adjacent erasable runs.
+				c = cell.Cell{Rune: ' ', FG: node.Color(rng.Intn(3))}
+			}
+			for i := range n {
+				next.Set(x+i, y, c)
+			}
+		}
+		var buf bytes.Buffer
+		r.Render(&buf, diff.Diff(prev, next))
+		s.apply(t, buf.String())
+		if !s.matches(next) {
+			t.Fatalf("frame %d: screen diverged after %q", frame, buf.String())
+		}
+		prev = next
+	}
+}
+
+func TestRendererScroll(t *testing.T) {
+	r := NewRenderer(20, 10)
+	var buf bytes.Buffer
+	r.RenderScroll(&buf, diff.Scroll{Top: 2, Bottom: 7, N: 1}, []diff.Change{{X: 0, Y: 7, Cells: cells("new", cell.Cell{})}})
+	// The region homes the cursor, so the new line is a relative move.
+	if out, want := buf.String(), "\x1b[0m\x1b[3;8r\x1b[1S\x1b[r\x1b[7Bnew"; out != want {
+		t.Fatalf("got  %q\nwant %q", out, want)
+	}
+	buf.Reset()
+	r.RenderScroll(&buf, diff.Scroll{Top: 0, Bottom: 9, N: -2}, nil)
+	if out := buf.String(); out != "\x1b[2T" {
+		t.Fatalf("whole-screen scroll = %q", out)
+	}
+}
+
+func TestRendererReproducesScrollingFrames(t *testing.T) {
+	const w, h = 24, 12
+	rng := rand.New(rand.NewSource(2))
+	r := NewRenderer(w, h)
+	s := newScreen(w, h)
+	var d diff.Differ
+	var lines []string
+	prev := cell.NewBuffer(w, h)
+	scrolls := 0
+	for frame := range 60 {
+		// A log pane in rows 1-10 tailing a growing list, or scrolled
+		// back a little, between a header and footer that change now
+		// and then.
+		for range rng.Intn(3) {
+			lines = append(lines, strconv.Itoa(len(lines))+strings.Repeat("ab", rng.Intn(8)))
+		}
+		end := max(0, len(lines)-rng.Intn(3))
+		next := cell.NewBuffer(w, h)
+		next.WriteString(0, 0, "head "+strconv.Itoa(frame/10), 1, 0, node.Bold)
+		for i := range 10 {
+			if j := end - 10 + i; j >= 0 {
+				next.WriteString(0, 1+i, lines[j], 2, 0, 0)
+			}
+		}
+		next.WriteString(0, h-1, "foot", 0, 4, 0)
+
+		sc, changes := d.DiffScroll(prev, next)
+		if sc.N != 0 {
+			scrolls++
+		}
+		var buf bytes.Buffer
+		r.RenderScroll(&buf, sc, changes)
+		s.apply(t, buf.String())
+		if !s.matches(next) {
+			t.Fatalf("frame %d: screen diverged after %q", frame, buf.String())
+		}
+		prev = next
+	}
+	if scrolls == 0 {
+		t.Fatal("no frame scrolled")
+	}
+}
+
+// bigFrame fills a w×h buffer with styled text, colored runs and blank
+// gaps, like a busy screen.
+func bigFrame(w, h int) *cell.Buffer {
+	b := cell.NewBuffer(w, h)
+	for y := range h {
+		for x := range w {
+			c := cell.Cell{Rune: ' '}
+			switch (x / 7) % 4 {
+			case 0:
+				c = cell.Cell{Rune: 'a' + rune((x+y)%26), FG: node.Color(1 + y%6)}
+			case 1:
+				c = cell.Cell{Rune: 'A' + rune(x%26), FG: node.RGB(uint8(x), uint8(y), 200), Style: node.Bold}
+			case 2:
+				c = cell.Cell{Rune: '-', BG: node.Color(8 + x%4)}
+			}
+			b.Set(x, y, c)
+		}
+	}
+	return b
+}
+
+func BenchmarkRenderFullFrame(b *testing.B) {
+	const w, h = 200, 60
+	changes := diff.Diff(cell.NewBuffer(w, h), bigFrame(w, h))
+	r := NewRenderer(w, h)
+	var n int
+	for b.Loop() {
+		r.Invalidate()
+		var buf countingWriter
+		r.Render(&buf, changes)
+		n = buf.Len()
+	}
+	b.ReportMetric(float64(n), "bytes/frame")
+}
+
+func BenchmarkRenderSparseUpdate(b *testing.B) {
+	const w, h = 200, 60
+	prev := bigFrame(w, h)
+	next := bigFrame(w, h)
+	for i := range 40 {
+		next.Set((i*37)%w, (i*11)%h, cell.Cell{Rune: '*', FG: 3})
+	}
+	changes := diff.Diff(prev, next)
+	r := NewRenderer(w, h)
+	var n int
+	for b.Loop() {
+		var buf countingWriter
+		r.Render(&buf, changes)
+		n = buf.Len()
+	}
+	b.ReportMetric(float64(n), "bytes/frame")
+}
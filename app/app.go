@@ -13,6 +13,7 @@ import (
 	"github.com/stukennedy/tooey/input"
 	"github.com/stukennedy/tooey/layout"
 	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/termcap"
 )
 
 // Msg is any message that can trigger a state update.
@@ -72,9 +73,49 @@ type ClickMsg struct {
 	Key  string
 }
 
+// DragMsg indicates mouse motion with the button held after a click.
+// Key is the ClickMsg key of the press that started the drag, X, Y the
+// current cell, and DX, DY the motion since the previous ClickMsg or
+// DragMsg of the same drag.
+type DragMsg struct {
+	X, Y   int
+	Key    string
+	DX, DY int
+}
+
 // Cmd is a function that runs asynchronously and returns a Msg.
 type Cmd func() Msg
 
+// focusRequestMsg asks the runtime to move focus; see RequestFocus.
+type focusRequestMsg struct {
+	key string
+}
+
+// RequestFocus returns a Cmd that moves focus to the focusable node
+// with the given key, if it is focusable in the active focus scope.
+// Update learns the outcome through FocusChangedMsg like any other
+// focus change.
+func RequestFocus(key string) Cmd {
+	return func() Msg { return focusRequestMsg{key: key} }
+}
+
+// FrameMsg is delivered at the start of a frame after RequestFrame.
+// Time is when the frame began; animations step from it.
+type FrameMsg struct {
+	Time time.Time
+}
+
+// frameRequestMsg asks the runtime for a FrameMsg; see RequestFrame.
+type frameRequestMsg struct{}
+
+// RequestFrame returns a Cmd that asks for a FrameMsg on the next
+// frame. Return it again from Update on each FrameMsg for as long as
+// an animation runs; once it stops, the loop sleeps until the next
+// message.
+func RequestFrame() Cmd {
+	return func() Msg { return frameRequestMsg{} }
+}
+
 // Sub is a long-running command that can send multiple messages via the send callback.
 // It returns a final Msg when done (or nil).
 type Sub func(send func(Msg)) Msg
@@ -125,8 +166,41 @@ type App[M any] struct {
 
 	// Input reader (defaults to os.Stdin).
 	Input io.Reader
+
+	// Caps overrides terminal capability detection (termcap.Detect).
+	// Colors and borders degrade to what it reports, and frames are
+	// drawn as synchronized updates when it has SyncOutput. Without
+	// it, a color depth set with ansi.SetColorLevel or
+	// ansi.SetTrueColor before Run is kept.
+	Caps *termcap.Caps
+
+	// QueryTerminal refines the detected capabilities by querying the
+	// terminal at startup (see termcap.Query). Input must already be in
+	// raw mode. Synchronized output is only ever detected this way, so
+	// without it (or a Caps with SyncOutput) frames aren't wrapped in
+	// mode 2026.
+	QueryTerminal bool
+
+	// MaxFPS caps how often frames are drawn (DefaultMaxFPS when 0).
+	// Messages arriving faster are batched into one frame.
+	MaxFPS int
+}
+
+// DefaultMaxFPS is the frame rate cap used when App.MaxFPS is 0.
+const DefaultMaxFPS = 30
+
+// frameInterval is the minimum time between frames.
+func (a *App[M]) frameInterval() time.Duration {
+	fps := a.MaxFPS
+	if fps <= 0 {
+		fps = DefaultMaxFPS
+	}
+	return time.Second / time.Duration(fps)
 }
 
+// queryTimeout bounds the wait for a terminal to answer queries.
+const queryTimeout = 200 * time.Millisecond
+
 // resolveClick converts a click hit path into the key to report,
 // moving focus to the clicked focusable. While a focus scope is active,
 // clicks that land outside the scope's subtree report no key and cannot
@@ -172,6 +246,22 @@ func (a *App[M]) Run(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	caps := termcap.Detect()
+	if a.Caps != nil {
+		caps = *a.Caps
+	}
+	if a.QueryTerminal {
+		caps, in = termcap.Query(caps, in, out, queryTimeout)
+	}
+	// An explicit Caps wins; otherwise detection yields to a color
+	// depth set with ansi.SetColorLevel or ansi.SetTrueColor.
+	if a.Caps != nil {
+		ansi.SetColorLevel(caps.Colors)
+	} else {
+		ansi.SetDetectedColorLevel(caps.Colors)
+	}
+	cell.SetASCIIBorders(!caps.Unicode)
+
 	// Terminal setup
 	ansi.EnterAltScreen(out)
 	ansi.HideCursor(out)
@@ -197,6 +287,12 @@ func (a *App[M]) Run(ctx context.Context) error {
 	var lastLayout *layout.LayoutNode
 	var lastFocused string
 
+	// Drag state: the press that started the current drag, and the last
+	// reported position.
+	var dragging bool
+	var dragKey string
+	var dragX, dragY int
+
 	// toMsg translates a raw key event into an app message; nil means
 	// the event is consumed (e.g. mouse button release).
 	toMsg := func(k input.Key) Msg {
@@ -210,13 +306,22 @@ func (a *App[M]) Run(ctx context.Context) error {
 		case input.MouseScrollDown:
 			return ScrollMsg{Delta: -3, X: k.MouseX, Y: k.MouseY}
 		case input.MouseRelease:
+			dragging = false
 			return nil
 		case input.MouseClick:
 			key := ""
 			if lastLayout != nil {
 				key = resolveClick(layout.HitTest(*lastLayout, k.MouseX, k.MouseY), fm)
 			}
+			dragging, dragKey, dragX, dragY = true, key, k.MouseX, k.MouseY
 			return ClickMsg{X: k.MouseX, Y: k.MouseY, Key: key}
+		case input.MouseDrag:
+			if !dragging || (k.MouseX == dragX && k.MouseY == dragY) {
+				return nil
+			}
+			msg := DragMsg{X: k.MouseX, Y: k.MouseY, Key: dragKey, DX: k.MouseX - dragX, DY: k.MouseY - dragY}
+			dragX, dragY = k.MouseX, k.MouseY
+			return msg
 		case input.Escape:
 			// Escape dismisses the active focus scope (modal) rather
 			// than arriving as a raw key.
@@ -236,15 +341,47 @@ func (a *App[M]) Run(ctx context.Context) error {
 	resizeCh := input.WatchResize(ctx)
 	cmdCh := make(chan Msg, 64)
 
-	// Frame rate limiter
-	frameTicker := time.NewTicker(33 * time.Millisecond) // ~30 FPS
-	defer frameTicker.Stop()
+	// Frames are event-driven: the loop sleeps until a message arrives,
+	// then draws once the frame interval since the last frame has
+	// passed, batching whatever came in meanwhile.
+	interval := a.frameInterval()
+	frameTimer := time.NewTimer(0)
+	defer frameTimer.Stop()
+	var lastFrame time.Time
 
 	needsRender := true
+	frameWanted := false // a RequestFrame is pending
 	msgs := make([]Msg, 0, 16)
 
+	collect := func(m Msg) {
+		if _, ok := m.(frameRequestMsg); ok {
+			frameWanted = true
+		} else {
+			msgs = append(msgs, m)
+		}
+		needsRender = true
+	}
+
+	// The renderer tracks the terminal's cursor and pen between frames
+	// and writes each frame at once, wrapped in a synchronized update
+	// when the terminal supports it.
+	renderer := ansi.NewRenderer(width, height)
+	renderer.Sync = caps.SyncOutput
+	var differ diff.Differ
+
+	// Subtrees marked WithMemo reuse their layout and painted cells
+	// across frames, and the two frame buffers are reused in turn.
+	var layoutCache layout.Cache
+	var paintCache cell.PaintCache
+	var spareBuf *cell.Buffer
+
 	for {
-		// Collect messages
+		// Wait for a message, or for the next frame when one is due.
+		var due <-chan time.Time
+		if needsRender {
+			frameTimer.Reset(interval - time.Since(lastFrame))
+			due = frameTimer.C
+		}
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
@@ -253,26 +390,27 @@ func (a *App[M]) Run(ctx context.Context) error {
 				return nil
 			}
 			if m := toMsg(k); m != nil {
-				msgs = append(msgs, m)
+				collect(m)
 			}
-			needsRender = true
+			continue
 		case r, ok := <-resizeCh:
 			if !ok {
+				resizeCh = nil
 				continue
 			}
 			width, height = r.Width, r.Height
-			ansi.ClearScreen(out) // clear stale content in newly exposed areas
-			prevBuf = nil         // force full redraw
-			msgs = append(msgs, ResizeMsg{Width: width, Height: height})
-			needsRender = true
+			renderer.Resize(width, height)
+			renderer.Clear() // clear stale content in newly exposed areas
+			prevBuf = nil    // force full redraw
+			collect(ResizeMsg{Width: width, Height: height})
+			continue
 		case cmdMsg := <-cmdCh:
-			msgs = append(msgs, cmdMsg)
-			needsRender = true
-		case <-frameTicker.C:
-			// Process batched messages
+			collect(cmdMsg)
+			continue
+		case <-due:
 		}
 
-		// Drain any additional pending messages
+		// Drain any additional pending messages into this frame
 		draining := true
 		for draining {
 			select {
@@ -282,35 +420,41 @@ func (a *App[M]) Run(ctx context.Context) error {
 					continue
 				}
 				if m := toMsg(k); m != nil {
-					msgs = append(msgs, m)
+					collect(m)
 				}
-				needsRender = true
 			case cmdMsg := <-cmdCh:
-				msgs = append(msgs, cmdMsg)
-				needsRender = true
+				collect(cmdMsg)
 			default:
 				draining = false
 			}
 		}
 
-		if !needsRender {
-			continue
+		lastFrame = time.Now()
+		if frameWanted {
+			frameWanted = false
+			msgs = append(msgs, FrameMsg{Time: lastFrame})
 		}
 
-		// Handle focus keys before update
+		// Handle focus keys and requests before update
 		for _, msg := range msgs {
-			if km, ok := msg.(KeyMsg); ok {
-				switch km.Key.Type {
+			switch msg := msg.(type) {
+			case KeyMsg:
+				switch msg.Key.Type {
 				case input.Tab:
 					fm.Next()
 				case input.ShiftTab:
 					fm.Prev()
 				}
+			case focusRequestMsg:
+				fm.Focus(msg.key)
 			}
 		}
 
 		// Process all messages through update
 		for _, msg := range msgs {
+			if _, ok := msg.(focusRequestMsg); ok {
+				continue // consumed by the focus manager above
+			}
 			result := a.Update(model, msg)
 			model = result.Model
 			if result.Quit {
@@ -339,7 +483,7 @@ func (a *App[M]) Run(ctx context.Context) error {
 
 		// Render pipeline
 		tree := a.View(model, fm.Current())
-		lt := layout.Layout(tree, width, height)
+		lt := layoutCache.Layout(tree, width, height)
 		fm.Update(lt)
 		lastLayout = &lt
 
@@ -351,19 +495,26 @@ func (a *App[M]) Run(ctx context.Context) error {
 			needsRender = true
 		}
 
-		buf := cell.NewBuffer(width, height)
-		cell.Paint(buf, lt)
+		buf := spareBuf
+		if buf == nil || buf.Width != width || buf.Height != height {
+			buf = cell.NewBuffer(width, height)
+		} else {
+			buf.Clear()
+		}
+		paintCache.Paint(buf, lt)
 
 		if prevBuf == nil {
 			prevBuf = cell.NewBuffer(width, height) // empty for first frame
 		}
 
-		changes := diff.Diff(prevBuf, buf)
-		ansi.Render(out, changes)
+		// Scrolling content (a log tail, a chat) moves with a terminal
+		// scroll instead of being redrawn row by row.
+		scroll, changes := differ.DiffScroll(prevBuf, buf)
+		renderer.RenderScroll(out, scroll, changes)
 
-		prevBuf = buf
+		spareBuf, prevBuf = prevBuf, buf
 		// Keep rendering pending if the frame itself queued messages
-		// (e.g. FocusChangedMsg) so they process on the next tick.
+		// (e.g. FocusChangedMsg) so they process on the next frame.
 		needsRender = len(msgs) > 0
 	}
 }
@@ -0,0 +1,182 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/termcap"
+)
+
+// frameWriter records each Write and signals when one contains marker.
+type frameWriter struct {
+	mu     sync.Mutex
+	writes []string
+	marker string
+	seen   chan struct{}
+}
+
+func (w *frameWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writes = append(w.writes, string(p))
+	if bytes.Contains(p, []byte(w.marker)) && w.seen != nil {
+		close(w.seen)
+		w.seen = nil
+	}
+	return len(p), nil
+}
+
+func TestRunSynchronizedFrame(t *testing.T) {
+	in, keys := io.Pipe()
+	out := &frameWriter{marker: "hello", seen: make(chan struct{})}
+	seen := out.seen
+	caps := termcap.Caps{Colors: termcap.ANSI256, Unicode: true, SyncOutput: true}
+	a := &App[int]{
+		Init: func() int { return 0 },
+		Update: func(m int, msg Msg) UpdateResult[int] {
+			if k, ok := msg.(KeyMsg); ok && k.Key.Rune == 'q' {
+				return Quit(m)
+			}
+			return NoCmd(m)
+		},
+		View:   func(int, string) node.Node { return node.Text("hello") },
+		Output: out,
+		Input:  in,
+		Caps:   &caps,
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- a.Run(context.Background()) }()
+	select {
+	case <-seen:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no frame rendered")
+	}
+	keys.Write([]byte("q"))
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	var frame string
+	for _, w := range out.writes {
+		if strings.Contains(w, "hello") {
+			frame = w
+		}
+	}
+	// The whole frame arrives in one write, inside a synchronized update.
+	if !strings.HasPrefix(frame, "\x1b[?2026h") || !strings.HasSuffix(frame, "\x1b[?2026l") {
+		t.Fatalf("frame not synchronized: %q", frame)
+	}
+	if !strings.Contains(frame, "\x1b[1;1H") {
+		t.Errorf("frame missing cursor move: %q", frame)
+	}
+}
+
+// runApp starts a reading keys from a pipe. Writing "q" to keys quits
+// it; wait returns once it has.
+func runApp[M any](t *testing.T, a *App[M]) (keys io.Writer, wait func()) {
+	t.Helper()
+	in, w := io.Pipe()
+	caps := termcap.Caps{Colors: termcap.ANSI256, Unicode: true}
+	a.Output, a.Input, a.Caps = io.Discard, in, &caps
+	update := a.Update
+	a.Update = func(m M, msg Msg) UpdateResult[M] {
+		if k, ok := msg.(KeyMsg); ok && k.Key.Rune == 'q' {
+			return Quit(m)
+		}
+		return update(m, msg)
+	}
+	done := make(chan error, 1)
+	go func() { done <- a.Run(context.Background()) }()
+	return w, func() {
+		t.Helper()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("app did not quit")
+		}
+	}
+}
+
+func TestRunSleepsWhenIdle(t *testing.T) {
+	var mu sync.Mutex
+	views := 0
+	keys, wait := runApp(t, &App[int]{
+		Init:   func() int { return 0 },
+		Update: func(m int, msg Msg) UpdateResult[int] { return NoCmd(m) },
+		View: func(int, string) node.Node {
+			mu.Lock()
+			views++
+			mu.Unlock()
+			return node.Text("idle")
+		},
+		MaxFPS: 100,
+	})
+	time.Sleep(100 * time.Millisecond)
+	// Events that turn into no message (a mouse release) don't draw.
+	for range 5 {
+		keys.Write([]byte("\x1b[<0;6;3m"))
+	}
+	time.Sleep(100 * time.Millisecond)
+	keys.Write([]byte("q"))
+	wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if views != 1 {
+		t.Fatalf("View called %d times while idle, want 1", views)
+	}
+}
+
+func TestRunRequestFrame(t *testing.T) {
+	const fps = 50
+	frames := make(chan time.Time, 16)
+	keys, wait := runApp(t, &App[int]{
+		Init: func() int { return 0 },
+		Update: func(m int, msg Msg) UpdateResult[int] {
+			switch msg := msg.(type) {
+			case KeyMsg:
+				return WithCmd(m, RequestFrame())
+			case FrameMsg:
+				frames <- msg.Time
+				if m++; m < 5 {
+					return WithCmd(m, RequestFrame())
+				}
+			}
+			return NoCmd(m)
+		},
+		View:   func(int, string) node.Node { return node.Text("anim") },
+		MaxFPS: fps,
+	})
+
+	// Any key starts the animation; it asks for frames until it's done.
+	keys.Write([]byte(" "))
+	time.Sleep(300 * time.Millisecond)
+	keys.Write([]byte("q"))
+	wait()
+
+	close(frames)
+	var times []time.Time
+	for tm := range frames {
+		times = append(times, tm)
+	}
+	if len(times) != 5 {
+		t.Fatalf("got %d frames, want 5", len(times))
+	}
+	for i := 1; i < len(times); i++ {
+		if d := times[i].Sub(times[i-1]); d < time.Second/fps {
+			t.Errorf("frame %d came %v after the last, want at least %v", i, d, time.Second/fps)
+		}
+	}
+}
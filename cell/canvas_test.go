@@ -0,0 +1,50 @@
+package cell
+
+import (
+	"testing"
+
+	"github.com/stukennedy/tooey/layout"
+	"github.com/stukennedy/tooey/node"
+)
+
+func TestPaintCanvasFillsResolvedRect(t *testing.T) {
+	var cols, rows int
+	canvas := node.Canvas(node.CanvasHalfBlock, func(s *node.Surface) {
+		cols, rows = s.Cols, s.Rows
+		s.Line(0, 0, s.W-1, 0, 1)
+		s.Set(s.W-1, s.H-1, 2)
+	})
+	root := node.Column(node.Text("title"), canvas).WithPadding(0, 0, 0, 1)
+
+	b := NewBuffer(6, 4)
+	Paint(b, layout.Layout(root, 6, 4))
+	if cols != 5 || rows != 3 {
+		t.Fatalf("surface = %dx%d cells, want 5x3", cols, rows)
+	}
+	want := []string{" title", " ▀▀▀▀▀", "      ", "     ▄"}
+	for y, w := range want {
+		if got := row(b, y); got != w {
+			t.Errorf("row %d = %q, want %q", y, got, w)
+		}
+	}
+	if fg := b.Get(1, 1).FG; fg != 1 {
+		t.Errorf("pixel FG = %v, want 1", fg)
+	}
+}
+
+func TestPaintCanvasClipsAndKeepsBG(t *testing.T) {
+	canvas := node.Canvas(node.CanvasBraille, func(s *node.Surface) {
+		s.FillRect(0, 0, s.W, s.H, 2)
+	}).WithSize(3, 1).WithBG(4)
+	// The box border clips the canvas to its 2-cell interior.
+	root := node.Box(node.BorderSingle, node.Row(canvas)).WithSize(4, 3)
+
+	b := NewBuffer(5, 3)
+	Paint(b, layout.Layout(root, 5, 3))
+	if got := row(b, 1); got != "│⣿⣿│ " {
+		t.Fatalf("clipped row = %q", got)
+	}
+	if c := b.Get(1, 1); c.FG != 2 || c.BG != 4 {
+		t.Errorf("cell colors = %v/%v, want 2/4", c.FG, c.BG)
+	}
+}
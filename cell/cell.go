@@ -12,6 +12,8 @@ type Cell struct {
 	FG    node.Color
 	BG    node.Color
 	Style node.StyleFlags
+	UL    node.Color // underline color; 0 follows FG
+	Link  string     // hyperlink URL, if any
 }
 
 // IsContinuation reports whether this cell is the right half of a wide
@@ -42,7 +44,7 @@ func (b *Buffer) inBounds(x, y int) bool {
 
 // Set writes a cell at (x, y), maintaining wide-rune invariants: a wide
 // rune always owns the continuation cell to its right (Rune 0, same
-// style), and overwriting either half of a wide pair blanks the other.
+// attributes), and overwriting either half of a wide pair blanks the other.
 func (b *Buffer) Set(x, y int, c Cell) {
 	if !b.inBounds(x, y) {
 		return
@@ -69,7 +71,8 @@ func (b *Buffer) Set(x, y int, c Cell) {
 	if textwidth.Rune(c.Rune) == 2 {
 		if x+1 >= b.Width {
 			// Wide rune doesn't fit in the last column; paint a blank.
-			b.Cells[i] = Cell{Rune: ' ', FG: c.FG, BG: c.BG, Style: c.Style}
+			c.Rune = ' '
+			b.Cells[i] = c
 			return
 		}
 		// Claiming the continuation cell may itself split a wide pair.
@@ -77,7 +80,8 @@ func (b *Buffer) Set(x, y int, c Cell) {
 			b.Cells[i+2].Rune = ' '
 		}
 		b.Cells[i] = c
-		b.Cells[i+1] = Cell{Rune: 0, FG: c.FG, BG: c.BG, Style: c.Style}
+		c.Rune = 0
+		b.Cells[i+1] = c
 		return
 	}
 
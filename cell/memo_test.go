@@ -0,0 +1,111 @@
+package cell
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stukennedy/tooey/layout"
+	"github.com/stukennedy/tooey/node"
+)
+
+func TestPaintCacheSkipsMemoizedCanvas(t *testing.T) {
+	draws := 0
+	canvas := node.Canvas(node.CanvasHalfBlock, func(s *node.Surface) {
+		draws++
+		s.Line(0, 0, s.W-1, 0, 2)
+	}).WithMemo("chart")
+	view := func(bg node.Color, spin string) node.Node {
+		return node.Box(node.BorderNone, node.Column(node.Text(spin), canvas)).WithBG(bg)
+	}
+
+	var pc PaintCache
+	var lc layout.Cache
+	frame := func(bg node.Color, spin string) *Buffer {
+		b := NewBuffer(8, 3)
+		pc.Paint(b, lc.Layout(view(bg, spin), 8, 3))
+		return b
+	}
+	uncached := func(bg node.Color, spin string) *Buffer {
+		defer func(n int) { draws = n }(draws)
+		b := NewBuffer(8, 3)
+		Paint(b, layout.Layout(view(bg, spin), 8, 3))
+		return b
+	}
+
+	frame(0, "|")
+	for _, spin := range []string{"/", "-", "\\"} {
+		if b := frame(0, spin); !slices.Equal(b.Cells, uncached(0, spin).Cells) {
+			t.Fatalf("frame %q differs from an uncached paint", spin)
+		}
+	}
+	if draws != 1 {
+		t.Fatalf("Draw called %d times, want 1", draws)
+	}
+
+	// A new background beneath the canvas means painting it again.
+	if b := frame(4, "|"); !slices.Equal(b.Cells, uncached(4, "|").Cells) {
+		t.Fatal("frame with a new background differs from an uncached paint")
+	}
+	if draws != 2 {
+		t.Fatalf("Draw called %d times after the background changed, want 2", draws)
+	}
+}
+
+func TestPaintCacheWideRuneAtEdge(t *testing.T) {
+	// The memoized text starts on the right half of a wide rune, so
+	// painting it blanks the rune's left half outside its rect.
+	view := node.Overlay(
+		node.Text("世界"),
+		node.Row(node.Text(" "), node.Text("ab").WithMemo("ab")),
+	)
+	var pc PaintCache
+	var lc layout.Cache
+	want := NewBuffer(6, 1)
+	Paint(want, layout.Layout(view, 6, 1))
+	for range 2 {
+		b := NewBuffer(6, 1)
+		pc.Paint(b, lc.Layout(view, 6, 1))
+		if !slices.Equal(b.Cells, want.Cells) {
+			t.Fatalf("got %q, want %q", row(b, 0), row(want, 0))
+		}
+	}
+}
+
+// dashboard is a large UI of memoized panels and one ticking spinner.
+func dashboard(tick int, memo bool) node.Node {
+	var panels []node.Node
+	for i := range 12 {
+		var lines []node.Node
+		for j := range 8 {
+			lines = append(lines, node.TextStyled(fmt.Sprintf("panel %d, item %d: some status text", i, j), node.Color(1+j%6), 0, 0))
+		}
+		p := node.Box(node.BorderRounded, node.Column(lines...)).WithFlex(1)
+		if memo {
+			p = p.WithMemo(fmt.Sprint("panel", i))
+		}
+		panels = append(panels, p)
+	}
+	var rows []node.Node
+	for i := 0; i < len(panels); i += 3 {
+		rows = append(rows, node.Row(panels[i:i+3]...).WithFlex(1))
+	}
+	spin := string(`|/-\`[tick%4])
+	return node.Column(append([]node.Node{node.Text(spin + " working")}, rows...)...)
+}
+
+func benchmarkFrame(b *testing.B, memo bool) {
+	const w, h = 200, 60
+	var lc layout.Cache
+	var pc PaintCache
+	buf := NewBuffer(w, h)
+	tick := 0
+	for b.Loop() {
+		tick++
+		buf.Clear()
+		pc.Paint(buf, lc.Layout(dashboard(tick, memo), w, h))
+	}
+}
+
+func BenchmarkFrame(b *testing.B)     { benchmarkFrame(b, false) }
+func BenchmarkFrameMemo(b *testing.B) { benchmarkFrame(b, true) }
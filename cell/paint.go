@@ -1,6 +1,8 @@
 package cell
 
 import (
+	"unicode/utf8"
+
 	"github.com/stukennedy/tooey/layout"
 	"github.com/stukennedy/tooey/node"
 	"github.com/stukennedy/tooey/textwidth"
@@ -8,10 +10,101 @@ import (
 
 // Paint renders a layout tree into the cell buffer.
 func Paint(buf *Buffer, tree layout.LayoutNode) {
-	paintNode(buf, tree, tree.Rect)
+	var pc *PaintCache
+	pc.paintNode(buf, tree, tree.Rect)
+}
+
+// PaintCache memoizes painting across frames for subtrees marked with
+// node.WithMemo. It records the cells a memoized subtree painted over
+// and what it left behind; when the subtree comes back with the same
+// rect and clip over the same cells, the result is copied in instead
+// of painted (so a Canvas's Draw isn't called). Entries unused for a
+// frame are dropped. The zero value is ready to use.
+type PaintCache struct {
+	prev, cur map[string]paintMemo
+}
+
+// paintMemo is one memoized subtree. The snapshots cover the painted
+// area plus a column either side, where wide-rune fixups can reach.
+type paintMemo struct {
+	rect, clip, area layout.Rect
+	before, after    []Cell
+}
+
+// Paint is like the package-level Paint, reusing memoized subtrees
+// from the previous call.
+func (pc *PaintCache) Paint(buf *Buffer, tree layout.LayoutNode) {
+	pc.prev, pc.cur = pc.cur, pc.prev
+	if pc.cur == nil {
+		pc.cur = make(map[string]paintMemo)
+	}
+	clear(pc.cur)
+	pc.paintNode(buf, tree, tree.Rect)
+}
+
+// paintMemoized paints a subtree with a Memo key, or copies in its
+// cells from the last frame.
+func (pc *PaintCache) paintMemoized(buf *Buffer, ln layout.LayoutNode, clip layout.Rect) {
+	key := ln.Node.Props.Memo
+	area := intersect(ln.Rect, clip)
+	area.X--
+	area.W += 2
+	area = intersect(area, layout.Rect{W: buf.Width, H: buf.Height})
+
+	m, ok := pc.cur[key]
+	if !ok {
+		m, ok = pc.prev[key]
+	}
+	if ok && m.rect == ln.Rect && m.clip == clip && m.area == area && buf.regionEqual(area, m.before) {
+		buf.copyRegion(area, m.after)
+		pc.cur[key] = m
+		return
+	}
+	m = paintMemo{rect: ln.Rect, clip: clip, area: area}
+	m.before = buf.region(area)
+	pc.paint(buf, ln, clip)
+	m.after = buf.region(area)
+	pc.cur[key] = m
+}
+
+// region returns a copy of the cells in r, row by row.
+func (b *Buffer) region(r layout.Rect) []Cell {
+	cells := make([]Cell, 0, r.W*r.H)
+	for y := r.Y; y < r.Y+r.H; y++ {
+		cells = append(cells, b.Cells[y*b.Width+r.X:y*b.Width+r.X+r.W]...)
+	}
+	return cells
+}
+
+// regionEqual reports whether the cells in r match cells (see region).
+func (b *Buffer) regionEqual(r layout.Rect, cells []Cell) bool {
+	for y := r.Y; y < r.Y+r.H; y++ {
+		row := b.Cells[y*b.Width+r.X : y*b.Width+r.X+r.W]
+		for x, c := range row {
+			if c != cells[(y-r.Y)*r.W+x] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// copyRegion writes cells (see region) back into r.
+func (b *Buffer) copyRegion(r layout.Rect, cells []Cell) {
+	for y := r.Y; y < r.Y+r.H; y++ {
+		copy(b.Cells[y*b.Width+r.X:y*b.Width+r.X+r.W], cells[(y-r.Y)*r.W:])
+	}
+}
+
+func (pc *PaintCache) paintNode(buf *Buffer, ln layout.LayoutNode, clip layout.Rect) {
+	if pc != nil && ln.Node.Props.Memo != "" {
+		pc.paintMemoized(buf, ln, clip)
+		return
+	}
+	pc.paint(buf, ln, clip)
 }
 
-func paintNode(buf *Buffer, ln layout.LayoutNode, clip layout.Rect) {
+func (pc *PaintCache) paint(buf *Buffer, ln layout.LayoutNode, clip layout.Rect) {
 	r := ln.Rect
 	n := ln.Node
 
@@ -20,6 +113,10 @@ func paintNode(buf *Buffer, ln layout.LayoutNode, clip layout.Rect) {
 		paintText(buf, ln, clip)
 	case node.BoxNode:
 		paintBox(buf, n, r, clip)
+	case node.CanvasNode:
+		paintCanvas(buf, n, r, clip)
+	case node.RuleNode:
+		paintRule(buf, n, r, clip)
 	}
 
 	// Recurse into children, clipping to the parent's content box
@@ -40,7 +137,7 @@ func paintNode(buf *Buffer, ln layout.LayoutNode, clip layout.Rect) {
 	}
 	childClip := intersect(content, clip)
 	for _, child := range ln.Children {
-		paintNode(buf, child, childClip)
+		pc.paintNode(buf, child, childClip)
 	}
 }
 
@@ -89,6 +186,8 @@ func paintText(buf *Buffer, ln layout.LayoutNode, clip layout.Rect) {
 					FG:    n.Props.FG,
 					BG:    n.Props.BG,
 					Style: n.Props.Style,
+					UL:    n.Props.UnderlineColor,
+					Link:  n.Props.Link,
 				})
 			}
 			col += w
@@ -114,6 +213,15 @@ func fillRect(buf *Buffer, r layout.Rect, c Cell) {
 	}
 }
 
+// asciiBorders draws box borders with ASCII for terminals that can't
+// display Unicode box drawing; see SetASCIIBorders.
+var asciiBorders bool
+
+// SetASCIIBorders makes Paint draw box borders and rules with "+", "-"
+// and "|" (and "=" for BorderDouble) instead of Unicode box drawing. app.Run
+// sets it when termcap reports no Unicode support.
+func SetASCIIBorders(enabled bool) { asciiBorders = enabled }
+
 func paintBox(buf *Buffer, n node.Node, r layout.Rect, clip layout.Rect) {
 	fg, bg, style := n.Props.FG, n.Props.BG, n.Props.Style
 
@@ -139,6 +247,12 @@ func paintBox(buf *Buffer, n node.Node, r layout.Rect, clip layout.Rect) {
 	default:
 		return
 	}
+	if asciiBorders {
+		tl, tr, bl, br, hz, vt = '+', '+', '+', '+', '-', '|'
+		if n.Props.Border == node.BorderDouble {
+			hz = '='
+		}
+	}
 
 	setClipped := func(x, y int, ch rune) {
 		if x >= clip.X && x < clip.X+clip.W && y >= clip.Y && y < clip.Y+clip.H {
@@ -165,6 +279,54 @@ func paintBox(buf *Buffer, n node.Node, r layout.Rect, clip layout.Rect) {
 	}
 }
 
+// paintRule fills a rule's rect with its line, in ASCII when
+// asciiBorders is set.
+func paintRule(buf *Buffer, n node.Node, r layout.Rect, clip layout.Rect) {
+	ch, _ := utf8.DecodeRuneInString(n.Props.Text)
+	if asciiBorders {
+		switch ch {
+		case '─':
+			ch = '-'
+		case '│':
+			ch = '|'
+		}
+	}
+	fillRect(buf, intersect(r, clip), Cell{Rune: ch, FG: n.Props.FG, BG: n.Props.BG, Style: n.Props.Style})
+}
+
+// paintCanvas runs a Canvas node's draw callback on a surface the size
+// of its rect and copies the drawn cells into the buffer.
+func paintCanvas(buf *Buffer, n node.Node, r layout.Rect, clip layout.Rect) {
+	clip = intersect(r, clip)
+	if clip.W == 0 || clip.H == 0 {
+		return
+	}
+	if n.Props.BG != 0 {
+		fillRect(buf, clip, Cell{Rune: ' ', FG: n.Props.FG, BG: n.Props.BG})
+	}
+	if n.Props.Draw == nil {
+		return
+	}
+	s := node.NewSurface(n.Props.CanvasMode, r.W, r.H)
+	n.Props.Draw(s)
+	for y := clip.Y; y < clip.Y+clip.H; y++ {
+		for x := clip.X; x < clip.X+clip.W; x++ {
+			ch, fg, bg := s.Cell(x-r.X, y-r.Y)
+			if ch == 0 {
+				continue
+			}
+			if bg == 0 {
+				bg = n.Props.BG
+			}
+			if textwidth.Rune(ch) == 2 && x+1 >= clip.X+clip.W {
+				// A wide label rune would spill out of the clip.
+				continue
+			}
+			buf.Set(x, y, Cell{Rune: ch, FG: fg, BG: bg, Style: n.Props.Style})
+		}
+	}
+}
+
 func intersect(a, b layout.Rect) layout.Rect {
 	x1 := max(a.X, b.X)
 	y1 := max(a.Y, b.Y)
@@ -59,3 +59,42 @@ func TestPaintColumn(t *testing.T) {
 		t.Fatalf("expected 'b' at (0,1), got %c", buf.Get(0, 1).Rune)
 	}
 }
+
+func TestPaintTextLink(t *testing.T) {
+	tree := node.Row(node.Text("see "), node.Text("文档").WithLink("https://x.dev"))
+	buf := NewBuffer(10, 1)
+	Paint(buf, layout.Layout(tree, 10, 1))
+	for x := range 10 {
+		want := ""
+		if x >= 4 && x < 8 {
+			want = "https://x.dev" // both halves of each wide rune
+		}
+		if got := buf.Get(x, 0).Link; got != want {
+			t.Errorf("x=%d: link %q, want %q", x, got, want)
+		}
+	}
+}
+
+func TestPaintASCIIBorders(t *testing.T) {
+	SetASCIIBorders(true)
+	defer SetASCIIBorders(false)
+
+	tree := node.Column(
+		node.Box(node.BorderRounded, node.Text("a")),
+		node.Box(node.BorderDouble, node.Text("b")),
+		node.HRule(),
+		node.Row(node.VRule(), node.Text("c")).WithSize(0, 1),
+	)
+	buf := NewBuffer(4, 8)
+	Paint(buf, layout.Layout(tree, 4, 8))
+	want := []string{"+--+", "|a |", "+--+", "+==+", "|b |", "+==+", "----", "|c  "}
+	for y, line := range want {
+		got := ""
+		for x := range 4 {
+			got += string(buf.Get(x, y).Rune)
+		}
+		if got != line {
+			t.Errorf("row %d = %q, want %q", y, got, line)
+		}
+	}
+}
@@ -0,0 +1,355 @@
+package component
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/textwidth"
+)
+
+// sparkBlocks are the eight bar heights used by sparklines and bar tops.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// SeriesColors is the default LineChart palette, the colorblind-safe
+// Okabe-Ito set. Terminals without truecolor get the nearest palette
+// colors.
+var SeriesColors = []node.Color{
+	node.RGB(86, 180, 233),  // sky blue
+	node.RGB(230, 159, 0),   // orange
+	node.RGB(0, 158, 115),   // bluish green
+	node.RGB(204, 121, 167), // reddish purple
+	node.RGB(240, 228, 66),  // yellow
+	node.RGB(0, 114, 178),   // blue
+	node.RGB(213, 94, 0),    // vermillion
+}
+
+// Series is one named data series of a chart.
+type Series struct {
+	Label string
+	Data  []float64
+	Color node.Color // default: the next color of the chart's palette
+}
+
+// Sparkline renders data as a single row of block elements scaled
+// between the smallest and largest value. It is a one-row node.Canvas
+// as wide as layout makes it (cap it with WithSize); data longer than
+// that is averaged into one bucket per cell.
+func Sparkline(data []float64, fg node.Color) node.Node {
+	lo, hi := dataRange(data)
+	return SparklineRange(data, lo, hi, fg)
+}
+
+// SparklineRange is Sparkline with a fixed scale, e.g. 0..100 for a
+// percentage. Values outside [lo, hi] are clamped.
+func SparklineRange(data []float64, lo, hi float64, fg node.Color) node.Node {
+	return node.Canvas(node.CanvasBraille, func(s *node.Surface) {
+		var b strings.Builder
+		for _, v := range resample(data, s.Cols) {
+			level := int(scale(v, lo, hi)*float64(len(sparkBlocks)-1) + 0.5)
+			level = min(max(level, 0), len(sparkBlocks)-1)
+			b.WriteRune(sparkBlocks[level])
+		}
+		cellText(s, 0, 0, b.String(), fg)
+	}).WithFlex(0).WithSize(0, 1)
+}
+
+// Bar is one bar of a BarChart.
+type Bar struct {
+	Label string
+	Value float64
+	Color node.Color // default: BarChart.FG
+}
+
+// BarChart draws vertical bars over a labelled value axis. Bar tops
+// use eighth-block elements, so heights resolve to 1/8 of a cell.
+// Negative values are drawn as empty bars.
+type BarChart struct {
+	Bars []Bar
+	// Max is the top of the scale; 0 uses the largest value.
+	Max float64
+	// BarWidth is each bar's width in cells; 0 shares the available
+	// width evenly.
+	BarWidth int
+	Gap      int // cells between bars (default 1)
+
+	FG      node.Color // default 6
+	AxisFG  node.Color // default 8
+	LabelFG node.Color
+}
+
+// Render builds the chart as a node.Canvas that fills the space layout
+// gives it (cap it with WithSize): the value axis on the left, bars
+// above a baseline, and bar labels below it when any bar has one.
+func (c BarChart) Render() node.Node {
+	return node.Canvas(node.CanvasBraille, c.draw)
+}
+
+func (c BarChart) draw(s *node.Surface) {
+	fg, axisFG := c.FG, c.AxisFG
+	if fg == 0 {
+		fg = 6
+	}
+	if axisFG == 0 {
+		axisFG = 8
+	}
+	gap := c.Gap
+	if gap <= 0 {
+		gap = 1
+	}
+	top := c.Max
+	if top <= 0 || !finite(top) {
+		top = 0
+		for _, b := range c.Bars {
+			if finite(b.Value) {
+				top = max(top, b.Value)
+			}
+		}
+	}
+	labels := false
+	for _, b := range c.Bars {
+		labels = labels || b.Label != ""
+	}
+
+	plotH := s.Rows - 1
+	if labels {
+		plotH--
+	}
+	hiLabel, loLabel := formatTick(top), formatTick(0)
+	gutterW := max(textwidth.String(hiLabel), textwidth.String(loLabel))
+	plotW := s.Cols - gutterW - 1
+	n := len(c.Bars)
+	if n == 0 || plotH <= 0 || plotW <= 0 {
+		return
+	}
+	bw := c.BarWidth
+	if bw <= 0 {
+		bw = max(1, (plotW-gap*(n-1))/n)
+	}
+
+	for r := range plotH {
+		cellText(s, 0, r, axisGutter(r, plotH, gutterW, hiLabel, loLabel), axisFG)
+		x := gutterW + 1
+		for i, b := range c.Bars {
+			if i > 0 {
+				x += gap
+			}
+			col := b.Color
+			if col == 0 {
+				col = fg
+			}
+			eighths := int(scale(b.Value, 0, top)*float64(plotH*8) + 0.5)
+			if top <= 0 {
+				eighths = 0
+			}
+			eighths = min(max(eighths, 0), plotH*8)
+			switch fill := eighths - (plotH-1-r)*8; {
+			case fill >= 8:
+				cellText(s, x, r, strings.Repeat("█", bw), col)
+			case fill > 0:
+				cellText(s, x, r, strings.Repeat(string(sparkBlocks[fill-1]), bw), col)
+			}
+			x += bw
+		}
+	}
+	cellText(s, gutterW, plotH, "└"+strings.Repeat("─", plotW), axisFG)
+	if labels {
+		x := gutterW + 1
+		for _, bar := range c.Bars {
+			cellText(s, x, plotH+1, alignCell(bar.Label, bw, AlignCenter), c.LabelFG)
+			x += bw + gap
+		}
+	}
+}
+
+// LineChart plots one or more series as lines on a Braille dot matrix,
+// giving each cell 2x4 dots of resolution. Points are spread evenly
+// across the width; series longer than the dot width are averaged
+// down. Where series cross in one cell, the later series' color wins.
+type LineChart struct {
+	Series []Series
+	// Min and Max fix the value axis; when both are zero it spans the
+	// data.
+	Min, Max float64
+
+	AxisFG node.Color // default 8
+	// Palette colors series that leave Color unset, in order; default
+	// SeriesColors.
+	Palette []node.Color
+	// Legend adds a row naming each labelled series in its color.
+	Legend bool
+}
+
+// Render builds the chart as a node.Canvas that fills the space layout
+// gives it (cap it with WithSize).
+func (c LineChart) Render() node.Node {
+	return node.Canvas(node.CanvasBraille, c.draw)
+}
+
+func (c LineChart) draw(s *node.Surface) {
+	axisFG := c.AxisFG
+	if axisFG == 0 {
+		axisFG = 8
+	}
+	lo, hi := c.Min, c.Max
+	if lo == 0 && hi == 0 || !finite(lo) || !finite(hi) {
+		var all []float64
+		for _, sr := range c.Series {
+			all = append(all, sr.Data...)
+		}
+		lo, hi = dataRange(all)
+	}
+
+	plotH := s.Rows - 1
+	if c.Legend {
+		plotH--
+	}
+	hiLabel, loLabel := formatTick(hi), formatTick(lo)
+	gutterW := max(textwidth.String(hiLabel), textwidth.String(loLabel))
+	plotW := s.Cols - gutterW - 1
+	if plotH <= 0 || plotW <= 0 {
+		return
+	}
+
+	// The plot starts after the gutter; a cell is 2x4 dots.
+	left := (gutterW + 1) * 2
+	dotsW, dotsH := plotW*2, plotH*4
+	for si, sr := range c.Series {
+		col := c.seriesColor(sr, si)
+		data := resample(sr.Data, dotsW)
+		prevX, prevY := 0, 0
+		for i, v := range data {
+			x := left
+			if len(data) > 1 {
+				x += i * (dotsW - 1) / (len(data) - 1)
+			}
+			y := dotsH - 1 - int(scale(v, lo, hi)*float64(dotsH-1)+0.5)
+			y = min(max(y, 0), dotsH-1)
+			if i == 0 {
+				s.Set(x, y, col)
+			} else {
+				s.Line(prevX, prevY, x, y, col)
+			}
+			prevX, prevY = x, y
+		}
+	}
+
+	for r := range plotH {
+		cellText(s, 0, r, axisGutter(r, plotH, gutterW, hiLabel, loLabel), axisFG)
+	}
+	cellText(s, gutterW, plotH, "└"+strings.Repeat("─", plotW), axisFG)
+	if c.Legend {
+		x := gutterW + 1
+		for i, sr := range c.Series {
+			if sr.Label == "" {
+				continue
+			}
+			item := "● " + sr.Label
+			cellText(s, x, plotH+1, item, c.seriesColor(sr, i))
+			x += textwidth.String(item) + 2
+		}
+	}
+}
+
+// seriesColor returns sr's color, or palette entry i when unset.
+func (c LineChart) seriesColor(sr Series, i int) node.Color {
+	if sr.Color != 0 {
+		return sr.Color
+	}
+	palette := c.Palette
+	if len(palette) == 0 {
+		palette = SeriesColors
+	}
+	return palette[i%len(palette)]
+}
+
+// cellText writes text starting at cell (col, row) of a Braille
+// surface.
+func cellText(s *node.Surface, col, row int, text string, fg node.Color) {
+	s.Text(col*2, row*4, text, fg)
+}
+
+// axisGutter returns the value-axis text for plot row r: the top and
+// bottom rows carry the scale labels.
+func axisGutter(r, plotH, w int, hi, lo string) string {
+	label, tick := "", "│"
+	switch {
+	case r == 0:
+		label, tick = hi, "┤"
+	case r == plotH-1:
+		label, tick = lo, "┤"
+	}
+	return alignCell(label, w, AlignRight) + tick
+}
+
+// resample averages data into n buckets when it has more than n points.
+// NaN and infinite values are dropped first.
+func resample(data []float64, n int) []float64 {
+	data = finiteValues(data)
+	if len(data) <= n || n <= 0 {
+		return data
+	}
+	out := make([]float64, n)
+	for i := range out {
+		from, to := i*len(data)/n, (i+1)*len(data)/n
+		sum := 0.0
+		for _, v := range data[from:to] {
+			sum += v
+		}
+		out[i] = sum / float64(to-from)
+	}
+	return out
+}
+
+// dataRange returns the smallest and largest finite value in data.
+func dataRange(data []float64) (lo, hi float64) {
+	data = finiteValues(data)
+	if len(data) == 0 {
+		return 0, 0
+	}
+	lo, hi = data[0], data[0]
+	for _, v := range data[1:] {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	return lo, hi
+}
+
+// finiteValues returns data without its NaN and infinite values,
+// copying only when there are any.
+func finiteValues(data []float64) []float64 {
+	for i, v := range data {
+		if !finite(v) {
+			out := slices.Clone(data[:i])
+			for _, v := range data[i+1:] {
+				if finite(v) {
+					out = append(out, v)
+				}
+			}
+			return out
+		}
+	}
+	return data
+}
+
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// scale maps v from [lo, hi] to [0, 1], clamping. An empty or
+// non-finite range, or a non-finite v, maps to 0.
+func scale(v, lo, hi float64) float64 {
+	if hi <= lo || !finite(lo) || !finite(hi) || !finite(v-lo) {
+		return 0
+	}
+	return min(max((v-lo)/(hi-lo), 0), 1)
+}
+
+// formatTick formats an axis label compactly.
+func formatTick(v float64) string {
+	if s := strconv.FormatFloat(v, 'f', -1, 64); len(s) <= 6 {
+		return s
+	}
+	return strconv.FormatFloat(v, 'g', 3, 64)
+}
@@ -0,0 +1,146 @@
+package component
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/tooeytest"
+)
+
+func TestSparkline(t *testing.T) {
+	got := tooeytest.RenderText(Sparkline([]float64{0, 1, 2, 3, 4, 5, 6, 7}, 0), 10, 1)
+	if got != "▁▂▃▄▅▆▇█" {
+		t.Fatalf("sparkline = %q", got)
+	}
+
+	// Eight points averaged into four buckets.
+	got = tooeytest.RenderText(SparklineRange([]float64{0, 0, 7, 7, 0, 0, 7, 7}, 0, 7, 0), 4, 1)
+	if got != "▁█▁█" {
+		t.Fatalf("resampled sparkline = %q", got)
+	}
+
+	got = tooeytest.RenderText(SparklineRange([]float64{-5, 50, 200}, 0, 100, 0), 3, 1)
+	if got != "▁▅█" {
+		t.Fatalf("clamped sparkline = %q", got)
+	}
+
+	// One row at the width layout gives it, under a title.
+	view := node.Column(node.Text("cpu"), Sparkline([]float64{0, 0, 7, 7, 0, 0, 7, 7}, 0).WithSize(4, 0), node.Text("end"))
+	tooeytest.AssertFrame(t, view, 10, 3, `
+		cpu
+		▁█▁█
+		end`)
+}
+
+func TestBarChartRender(t *testing.T) {
+	c := BarChart{Bars: []Bar{
+		{Label: "a", Value: 4},
+		{Label: "bb", Value: 8, Color: node.RGB(255, 0, 0)},
+		{Label: "c", Value: 1},
+	}}
+	n := c.Render()
+	tooeytest.AssertFrame(t, n, 14, 6, `
+		8┤    ███
+		 │    ███
+		 │███ ███
+		0┤███ ███ ▄▄▄
+		 └────────────
+		   a  bb   c`)
+
+	buf := tooeytest.Render(n, 14, 6)
+	if fg := buf.Get(2, 3).FG; fg != 6 {
+		t.Errorf("default bar color = %v, want 6", fg)
+	}
+	if fg := buf.Get(6, 3).FG; fg != node.RGB(255, 0, 0) {
+		t.Errorf("bar color = %v, want RGB red", fg)
+	}
+}
+
+func TestLineChartRender(t *testing.T) {
+	c := LineChart{
+		Series: []Series{{Label: "flat", Data: []float64{5, 5, 5}}},
+		Min:    0, Max: 5,
+		Legend: true,
+	}
+	tooeytest.AssertFrame(t, c.Render(), 8, 4, `
+		5┤⠉⠉⠉⠉⠉⠉
+		0┤
+		 └──────
+		  ● flat`)
+
+	// A rising line crosses every dot row: bottom-left to top-right.
+	c = LineChart{Series: []Series{{Data: []float64{0, 1}}}}
+	got := tooeytest.RenderText(c.Render(), 4, 3)
+	want := "1┤ ⡼\n" +
+		"0┤⡼⠁\n" +
+		" └──"
+	if got != want {
+		t.Fatalf("rising line:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestLineChartSeriesColors(t *testing.T) {
+	c := LineChart{Series: []Series{
+		{Data: []float64{1, 1}},
+		{Data: []float64{0, 0}, Color: 9},
+	}}
+	buf := tooeytest.Render(c.Render(), 4, 3)
+	if fg := buf.Get(2, 0).FG; fg != SeriesColors[0] {
+		t.Errorf("first series color = %v, want palette %v", fg, SeriesColors[0])
+	}
+	if fg := buf.Get(2, 1).FG; fg != 9 {
+		t.Errorf("second series color = %v, want 9", fg)
+	}
+
+	c.Palette = []node.Color{3}
+	if fg := tooeytest.Render(c.Render(), 4, 3).Get(2, 0).FG; fg != 3 {
+		t.Errorf("palette color = %v, want 3", fg)
+	}
+}
+
+func TestChartSizesFromLayout(t *testing.T) {
+	// The chart takes the space left under the title, capped in width.
+	c := BarChart{Bars: []Bar{{Value: 2}, {Value: 1}}}
+	view := node.Column(node.Text("load"), c.Render().WithSize(7, 0))
+	tooeytest.AssertFrame(t, view, 10, 4, `
+		load
+		2┤██
+		0┤██ ██
+		 └─────`)
+}
+
+func TestChartsSkipNonFinite(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+
+	got := tooeytest.RenderText(Sparkline([]float64{0, nan, 7, inf, math.Inf(-1)}, 0), 5, 1)
+	if got != "▁█" {
+		t.Fatalf("sparkline = %q, want %q", got, "▁█")
+	}
+	got = tooeytest.RenderText(SparklineRange([]float64{1, 2}, nan, inf, 0), 2, 1)
+	if got != "▁▁" {
+		t.Fatalf("sparkline with non-finite range = %q", got)
+	}
+
+	bars := BarChart{Bars: []Bar{{Value: 4}, {Value: nan}, {Value: inf}}}
+	frame := tooeytest.RenderText(bars.Render(), 12, 4)
+	if strings.Contains(frame, "NaN") || strings.Contains(frame, "Inf") {
+		t.Fatalf("bar chart labels:\n%s", frame)
+	}
+	if !strings.HasPrefix(frame, "4┤") {
+		t.Fatalf("bar chart should scale to the finite values:\n%s", frame)
+	}
+
+	// The line skips the missing points and still spans the data.
+	lines := LineChart{Series: []Series{
+		{Data: []float64{1, nan, 3}},
+		{Data: []float64{inf, math.Inf(-1)}},
+	}}
+	tooeytest.AssertFrame(t, lines.Render(), 4, 3, `
+		3┤ ⡼
+		1┤⡼⠁
+		 └──`)
+	lines.Min, lines.Max = math.Inf(-1), inf
+	tooeytest.Render(lines.Render(), 20, 6)
+}
@@ -0,0 +1,138 @@
+package component
+
+import (
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/node"
+)
+
+// Checkbox is a keyed, focusable boolean control rendered as "[x] Label".
+// Space, Enter, or a click toggles it; a disabled checkbox is dimmed and
+// drops out of the focus order.
+type Checkbox struct {
+	Key      string
+	Label    string
+	Checked  bool
+	Disabled bool
+
+	FG      node.Color
+	FocusFG node.Color // default 6
+}
+
+// Update toggles the checkbox when msg activates it (see Switch.Update)
+// and returns the updated Checkbox. Pass the focused key from View or a
+// FocusChangedMsg mirror.
+func (c Checkbox) Update(msg app.Msg, focused string) Checkbox {
+	if !c.Disabled && activated(msg, c.Key, focused) {
+		c.Checked = !c.Checked
+	}
+	return c
+}
+
+// Render builds the checkbox as a single keyed text node.
+func (c Checkbox) Render(focused string) node.Node {
+	box := "[ ] "
+	if c.Checked {
+		box = "[x] "
+	}
+	return toggleNode(box+c.Label, c.Key, focused, c.Disabled, c.FG, c.FocusFG)
+}
+
+// Switch is an on/off toggle rendered as a small track with a knob,
+// followed by its label. It behaves exactly like Checkbox.
+type Switch struct {
+	Key      string
+	Label    string
+	On       bool
+	Disabled bool
+
+	FG      node.Color
+	OnFG    node.Color // track color when on (default 2)
+	FocusFG node.Color // default 6
+}
+
+// Update flips the switch when msg activates it: a ClickMsg on Key, or
+// Space/Enter while Key is focused. Disabled switches ignore input.
+func (s Switch) Update(msg app.Msg, focused string) Switch {
+	if !s.Disabled && activated(msg, s.Key, focused) {
+		s.On = !s.On
+	}
+	return s
+}
+
+// Render builds the switch as a Row keyed with Key.
+func (s Switch) Render(focused string) node.Node {
+	track, trackFG := "●━━", s.FG
+	if s.On {
+		track, trackFG = "━━●", s.OnFG
+		if trackFG == 0 {
+			trackFG = 2
+		}
+	}
+	style := node.StyleFlags(0)
+	if s.Disabled {
+		trackFG, style = 8, node.Dim
+	}
+	label := node.TextStyled(s.Label, s.FG, 0, 0)
+	switch {
+	case s.Disabled:
+		label = node.TextStyled(s.Label, 8, 0, node.Dim)
+	case s.Key != "" && focused == s.Key:
+		label = node.TextStyled(s.Label, focusColor(s.FocusFG), 0, node.Bold)
+	}
+	n := node.Row(node.TextStyled(track+" ", trackFG, 0, style), label)
+	if s.Key != "" {
+		n = n.WithKey(s.Key)
+		if !s.Disabled {
+			n = n.WithFocusable()
+		}
+	}
+	return n
+}
+
+// activated reports whether msg toggles the control keyed key: a click
+// on it, or Space/Enter while it has focus.
+func activated(msg app.Msg, key, focused string) bool {
+	if key == "" {
+		return false
+	}
+	switch msg := msg.(type) {
+	case app.ClickMsg:
+		return msg.Key == key
+	case app.KeyMsg:
+		if focused != key {
+			return false
+		}
+		k := msg.Key
+		return k.Type == input.Enter || (k.Type == input.RuneKey && k.Rune == ' ')
+	}
+	return false
+}
+
+// toggleNode styles a control's text for its focus/disabled state and
+// keys it (focusable unless disabled) when key is set.
+func toggleNode(text, key, focused string, disabled bool, fg, focusFG node.Color) node.Node {
+	var n node.Node
+	switch {
+	case disabled:
+		n = node.TextStyled(text, 8, 0, node.Dim)
+	case key != "" && focused == key:
+		n = node.TextStyled(text, focusColor(focusFG), 0, node.Bold)
+	default:
+		n = node.TextStyled(text, fg, 0, 0)
+	}
+	if key != "" {
+		n = n.WithKey(key)
+		if !disabled {
+			n = n.WithFocusable()
+		}
+	}
+	return n
+}
+
+func focusColor(c node.Color) node.Color {
+	if c == 0 {
+		return 6
+	}
+	return c
+}
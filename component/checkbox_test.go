@@ -0,0 +1,101 @@
+package component
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/focus"
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/layout"
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/tooeytest"
+)
+
+var (
+	spaceKey = app.KeyMsg{Key: input.Key{Type: input.RuneKey, Rune: ' '}}
+	enterKey = app.KeyMsg{Key: input.Key{Type: input.Enter}}
+)
+
+func TestCheckboxToggle(t *testing.T) {
+	c := Checkbox{Key: "cb", Label: "Wrap lines"}
+	tooeytest.AssertFrame(t, c.Render(""), 20, 1, `[ ] Wrap lines`)
+
+	c = c.Update(spaceKey, "other")
+	if c.Checked {
+		t.Fatal("space without focus should not toggle")
+	}
+	c = c.Update(spaceKey, "cb")
+	if !c.Checked {
+		t.Fatal("space while focused should toggle")
+	}
+	c = c.Update(enterKey, "cb")
+	c = c.Update(app.ClickMsg{Key: "cb"}, "")
+	if !c.Checked {
+		t.Fatal("enter then click should toggle twice")
+	}
+	tooeytest.AssertFrame(t, c.Render("cb"), 20, 1, `[x] Wrap lines`)
+}
+
+func TestCheckboxDisabled(t *testing.T) {
+	c := Checkbox{Key: "cb", Label: "Locked", Disabled: true}
+	if c.Update(app.ClickMsg{Key: "cb"}, "cb").Checked {
+		t.Fatal("disabled checkbox should ignore clicks")
+	}
+	n := c.Render("")
+	if n.Props.Focusable {
+		t.Fatal("disabled checkbox should not be focusable")
+	}
+	if n.Props.Style&node.Dim == 0 {
+		t.Fatal("disabled checkbox should be dimmed")
+	}
+}
+
+func TestSwitch(t *testing.T) {
+	s := Switch{Key: "sw", Label: "Dark mode"}
+	tooeytest.AssertFrame(t, s.Render(""), 20, 1, `●━━ Dark mode`)
+	s = s.Update(app.ClickMsg{Key: "sw"}, "")
+	if !s.On {
+		t.Fatal("click should switch on")
+	}
+	tooeytest.AssertFrame(t, s.Render(""), 20, 1, `━━● Dark mode`)
+}
+
+func TestRadioGroup(t *testing.T) {
+	r := RadioGroup{Key: "rg", Options: []string{"Low", "High"}}
+	tooeytest.AssertFrame(t, r.Render(""), 20, 2, `
+		(•) Low
+		( ) High`)
+
+	r = r.Update(app.KeyMsg{Key: input.Key{Type: input.Down}}, "rg")
+	if r.Selected != 1 || r.Value() != "High" {
+		t.Fatalf("down should select next, got %d", r.Selected)
+	}
+	r = r.Update(app.ClickMsg{Key: "rg-0"}, "")
+	if r.Selected != 0 {
+		t.Fatal("click on option should select it")
+	}
+	r = r.Update(app.ClickMsg{Key: "rg-9"}, "")
+	if r.Selected != 0 {
+		t.Fatal("out-of-range option key should be ignored")
+	}
+}
+
+func TestControlsFocusOrder(t *testing.T) {
+	view := node.Column(
+		Checkbox{Key: "a", Label: "A"}.Render(""),
+		Checkbox{Key: "b", Label: "B", Disabled: true}.Render(""),
+		RadioGroup{Key: "c", Options: []string{"x", "y"}}.Render(""),
+		Switch{Key: "d", Label: "D"}.Render(""),
+	)
+	fm := focus.NewManager()
+	fm.Update(layout.Layout(view, 20, 10))
+	var order []string
+	for range 3 {
+		order = append(order, fm.Current())
+		fm.Next()
+	}
+	if want := []string{"a", "c", "d"}; !slices.Equal(order, want) {
+		t.Fatalf("focus order = %v, want %v", order, want)
+	}
+}
@@ -0,0 +1,368 @@
+package component
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/textwidth"
+)
+
+// Align positions text within a column.
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// TableColumn describes one DataTable column. A column with Width set
+// is fixed; one with Flex set shares the space left over by the others
+// in proportion to Flex; otherwise it is as wide as its widest cell.
+// MinWidth bounds flex and content-sized columns from below.
+type TableColumn struct {
+	Title    string
+	Width    int
+	Flex     int
+	MinWidth int
+	Align    Align
+
+	// Less orders two cell values when sorting by this column. Nil
+	// compares numerically when both parse as numbers, else as strings.
+	Less func(a, b string) bool
+	// NoSort disables click-to-sort on the header.
+	NoSort bool
+}
+
+// DataTable is an interactive grid: sized and aligned columns, a sticky
+// header over a scrolling body, click-to-sort headers, and row or cell
+// selection.
+//
+// The table is a single focus stop keyed with Key. Header cells are
+// keyed "<Key>-h-<col>" and rows "<Key>-r-<row>"; with CellSelect, each
+// cell is keyed "<Key>-c-<row>-<col>". Forward key and click messages
+// to Update.
+type DataTable struct {
+	Key     string
+	Columns []TableColumn
+	Rows    [][]string
+
+	// Row and Col are the selected row (index into Rows) and, with
+	// CellSelect, the selected column.
+	Row        int
+	Col        int
+	CellSelect bool
+
+	// SortCol is the column Rows are sorted by (-1 = unsorted).
+	SortCol  int
+	SortDesc bool
+
+	// Height is the number of visible body rows (0 = all); Offset is
+	// the first visible one. Update keeps the selection in view.
+	Height int
+	Offset int
+
+	// ColGap is the number of spaces between columns (default 2).
+	ColGap int
+
+	HeaderFG   node.Color
+	HeaderBG   node.Color
+	FG         node.Color
+	BG         node.Color
+	SelectedFG node.Color
+	SelectedBG node.Color // default 6 while focused
+}
+
+// NewDataTable creates an unsorted table.
+func NewDataTable(key string, cols []TableColumn, rows [][]string) DataTable {
+	return DataTable{Key: key, Columns: cols, Rows: rows, SortCol: -1}
+}
+
+// Update handles header clicks (sorting), row and cell clicks
+// (selection), and navigation keys while focused is the table's Key.
+func (t DataTable) Update(msg app.Msg, focused string) DataTable {
+	switch msg := msg.(type) {
+	case app.ClickMsg:
+		rest, ok := strings.CutPrefix(msg.Key, t.Key+"-")
+		if !ok {
+			return t
+		}
+		kind, idx, _ := strings.Cut(rest, "-")
+		switch kind {
+		case "h":
+			if c, err := strconv.Atoi(idx); err == nil {
+				t = t.SortBy(c)
+			}
+		case "r":
+			if r, err := strconv.Atoi(idx); err == nil && r < len(t.Rows) {
+				t.Row = r
+			}
+		case "c":
+			rs, cs, _ := strings.Cut(idx, "-")
+			r, err1 := strconv.Atoi(rs)
+			c, err2 := strconv.Atoi(cs)
+			if err1 == nil && err2 == nil && r < len(t.Rows) && c < len(t.Columns) {
+				t.Row, t.Col = r, c
+			}
+		}
+	case app.KeyMsg:
+		if focused != t.Key || len(t.Rows) == 0 {
+			return t
+		}
+		page := max(t.Height, 1)
+		switch msg.Key.Type {
+		case input.Up:
+			t.Row--
+		case input.Down:
+			t.Row++
+		case input.PageUp:
+			t.Row -= page
+		case input.PageDown:
+			t.Row += page
+		case input.Home:
+			t.Row = 0
+		case input.End:
+			t.Row = len(t.Rows) - 1
+		case input.Left:
+			if t.CellSelect {
+				t.Col--
+			}
+		case input.Right:
+			if t.CellSelect {
+				t.Col++
+			}
+		}
+		t.Row = clampInt(t.Row, 0, len(t.Rows)-1)
+		t.Col = clampInt(t.Col, 0, max(len(t.Columns)-1, 0))
+	}
+	t.Offset = t.scrollOffset()
+	return t
+}
+
+// SortBy sorts Rows by column c, reversing the order when already
+// sorted by c. The selection follows the selected row.
+func (t DataTable) SortBy(c int) DataTable {
+	if c < 0 || c >= len(t.Columns) || t.Columns[c].NoSort {
+		return t
+	}
+	if t.SortCol == c {
+		t.SortDesc = !t.SortDesc
+	} else {
+		t.SortCol, t.SortDesc = c, false
+	}
+
+	less := t.Columns[c].Less
+	if less == nil {
+		less = lessCell
+	}
+	// Sort a permutation so the selection can follow its row.
+	order := make([]int, len(t.Rows))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		x, y := cellAt(t.Rows[a], c), cellAt(t.Rows[b], c)
+		if t.SortDesc {
+			x, y = y, x
+		}
+		switch {
+		case less(x, y):
+			return -1
+		case less(y, x):
+			return 1
+		}
+		return 0
+	})
+	rows := make([][]string, len(order))
+	row := t.Row
+	for i, from := range order {
+		rows[i] = t.Rows[from]
+		if from == t.Row {
+			row = i
+		}
+	}
+	t.Rows, t.Row = rows, row
+	t.Offset = t.scrollOffset()
+	return t
+}
+
+// lessCell compares numerically when both cells are numbers.
+func lessCell(a, b string) bool {
+	x, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	y, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if errA == nil && errB == nil {
+		return x < y
+	}
+	return cmp.Less(a, b)
+}
+
+func cellAt(row []string, c int) string {
+	if c < len(row) {
+		return row[c]
+	}
+	return ""
+}
+
+// SelectedRow returns the selected row's cells, or nil.
+func (t DataTable) SelectedRow() []string {
+	if t.Row < 0 || t.Row >= len(t.Rows) {
+		return nil
+	}
+	return t.Rows[t.Row]
+}
+
+func (t DataTable) scrollOffset() int {
+	if t.Height <= 0 {
+		return 0
+	}
+	off := clampInt(t.Offset, 0, max(len(t.Rows)-t.Height, 0))
+	if t.Row < off {
+		off = max(t.Row, 0)
+	} else if t.Row >= off+t.Height {
+		off = t.Row - t.Height + 1
+	}
+	return off
+}
+
+func (t DataTable) gap() int {
+	if t.ColGap <= 0 {
+		return 2
+	}
+	return t.ColGap
+}
+
+// columnWidths resolves column widths for a table width (<= 0 sizes
+// flex columns to their content).
+func (t DataTable) columnWidths(width int) []int {
+	widths := make([]int, len(t.Columns))
+	used, flex := t.gap()*max(len(t.Columns)-1, 0), 0
+	for i, col := range t.Columns {
+		switch {
+		case col.Width > 0:
+			widths[i] = col.Width
+		case col.Flex > 0 && width > 0:
+			flex += col.Flex
+			continue
+		default:
+			w := textwidth.String(col.Title)
+			if !col.NoSort {
+				w += 2 // room for the sort arrow
+			}
+			for _, r := range t.Rows {
+				w = max(w, textwidth.String(cellAt(r, i)))
+			}
+			widths[i] = max(w, col.MinWidth)
+		}
+		used += widths[i]
+	}
+	if flex == 0 {
+		return widths
+	}
+	// Share the remaining space among flex columns, the last one taking
+	// the rounding remainder.
+	room := max(width-used, 0)
+	last := -1
+	given := 0
+	for i, col := range t.Columns {
+		if col.Width > 0 || col.Flex <= 0 {
+			continue
+		}
+		widths[i] = room * col.Flex / flex
+		given += widths[i]
+		last = i
+	}
+	widths[last] += room - given
+	for i, col := range t.Columns {
+		if col.Width <= 0 && col.Flex > 0 {
+			widths[i] = max(widths[i], col.MinWidth)
+		}
+	}
+	return widths
+}
+
+// alignCell truncates s to w cells and pads it according to a.
+func alignCell(s string, w int, a Align) string {
+	s = textwidth.Truncate(s, w)
+	pad := w - textwidth.String(s)
+	switch a {
+	case AlignRight:
+		return strings.Repeat(" ", pad) + s
+	case AlignCenter:
+		return strings.Repeat(" ", pad/2) + s + strings.Repeat(" ", pad-pad/2)
+	}
+	return s + strings.Repeat(" ", pad)
+}
+
+// Render builds the table for the given width: the header row, then the
+// visible slice of body rows. Pass the focused key so the selection is
+// highlighted only while the table has focus.
+func (t DataTable) Render(width int, focused string) node.Node {
+	widths := t.columnWidths(width)
+	gap := strings.Repeat(" ", t.gap())
+
+	header := make([]node.Node, 0, 2*len(t.Columns))
+	for c, col := range t.Columns {
+		title := col.Title
+		if c == t.SortCol {
+			if t.SortDesc {
+				title += " ▼"
+			} else {
+				title += " ▲"
+			}
+		}
+		if c > 0 {
+			header = append(header, node.TextStyled(gap, t.HeaderFG, t.HeaderBG, 0))
+		}
+		header = append(header, node.TextStyled(alignCell(title, widths[c], col.Align), t.HeaderFG, t.HeaderBG, node.Bold).
+			WithNoWrap().WithKey(t.Key+"-h-"+strconv.Itoa(c)))
+	}
+
+	start, end := 0, len(t.Rows)
+	if t.Height > 0 {
+		start = clampInt(t.Offset, 0, max(len(t.Rows)-t.Height, 0))
+		end = min(start+t.Height, len(t.Rows))
+	}
+	hasFocus := t.Key != "" && focused == t.Key
+
+	body := make([]node.Node, 0, end-start)
+	for r := start; r < end; r++ {
+		fg, bg, style := t.FG, t.BG, node.StyleFlags(0)
+		selRow := r == t.Row && !t.CellSelect
+		if selRow {
+			fg, bg, style = t.SelectedFG, t.SelectedBG, node.Bold
+			if hasFocus && bg == 0 {
+				fg, bg = 0, 6
+			}
+		}
+		cells := make([]node.Node, 0, 2*len(t.Columns))
+		for c, col := range t.Columns {
+			if c > 0 {
+				cells = append(cells, node.TextStyled(gap, fg, bg, 0))
+			}
+			cfg, cbg, cstyle := fg, bg, style
+			if t.CellSelect && r == t.Row && c == t.Col {
+				cstyle = node.Bold
+				if hasFocus {
+					cstyle |= node.Reverse
+				}
+			}
+			n := node.TextStyled(alignCell(cellAt(t.Rows[r], c), widths[c], col.Align), cfg, cbg, cstyle).WithNoWrap()
+			if t.CellSelect {
+				n = n.WithKey(t.Key + "-c-" + strconv.Itoa(r) + "-" + strconv.Itoa(c))
+			}
+			cells = append(cells, n)
+		}
+		body = append(body, node.Row(cells...).WithKey(t.Key+"-r-"+strconv.Itoa(r)))
+	}
+
+	table := node.Column(node.Row(header...), node.Column(body...))
+	if t.Key != "" {
+		table = table.WithKey(t.Key).WithFocusable()
+	}
+	return table
+}
@@ -0,0 +1,79 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/tooeytest"
+)
+
+func testDataTable() DataTable {
+	return NewDataTable("dt",
+		[]TableColumn{
+			{Title: "NAME", Flex: 1},
+			{Title: "CPU", Width: 5, Align: AlignRight},
+		},
+		[][]string{
+			{"nginx", "12"},
+			{"postgres-primary", "3"},
+			{"redis", "40"},
+		})
+}
+
+func TestDataTableLayout(t *testing.T) {
+	tooeytest.AssertFrame(t, testDataTable().Render(20, ""), 20, 4, `
+		NAME             CPU
+		nginx             12
+		postgres-pri…      3
+		redis             40`)
+}
+
+func TestDataTableSortFollowsSelection(t *testing.T) {
+	dt := testDataTable()
+	dt.Row = 1 // postgres
+	dt = dt.Update(app.ClickMsg{Key: "dt-h-1"}, "")
+	if dt.SortCol != 1 || dt.SortDesc {
+		t.Fatal("header click should sort ascending")
+	}
+	if got := dt.Rows[0][0]; got != "postgres-primary" {
+		t.Fatalf("numeric sort: first row %q", got)
+	}
+	if dt.SelectedRow()[0] != "postgres-primary" {
+		t.Fatal("selection should follow its row")
+	}
+	dt = dt.Update(app.ClickMsg{Key: "dt-h-1"}, "")
+	if !dt.SortDesc || dt.Rows[0][0] != "redis" {
+		t.Fatal("second click should sort descending")
+	}
+	got := tooeytest.RenderText(dt.Render(20, ""), 20, 1)
+	if got != "NAME           CPU ▼" {
+		t.Fatalf("header should show sort arrow, got %q", got)
+	}
+}
+
+func TestDataTableScrollKeepsHeader(t *testing.T) {
+	dt := testDataTable()
+	dt.Height = 1
+	dt = dt.Update(app.KeyMsg{Key: input.Key{Type: input.End}}, "dt")
+	if dt.Row != 2 || dt.Offset != 2 {
+		t.Fatalf("row %d offset %d, want 2/2", dt.Row, dt.Offset)
+	}
+	tooeytest.AssertFrame(t, dt.Render(20, "dt"), 20, 2, `
+		NAME             CPU
+		redis             40`)
+}
+
+func TestDataTableCellSelection(t *testing.T) {
+	dt := testDataTable()
+	dt.CellSelect = true
+	dt = dt.Update(app.KeyMsg{Key: input.Key{Type: input.Right}}, "dt")
+	dt = dt.Update(app.KeyMsg{Key: input.Key{Type: input.Down}}, "dt")
+	if dt.Row != 1 || dt.Col != 1 {
+		t.Fatalf("cell = (%d,%d), want (1,1)", dt.Row, dt.Col)
+	}
+	dt = dt.Update(app.ClickMsg{Key: "dt-c-2-0"}, "dt")
+	if dt.Row != 2 || dt.Col != 0 {
+		t.Fatalf("click cell = (%d,%d), want (2,0)", dt.Row, dt.Col)
+	}
+}
@@ -0,0 +1,477 @@
+package component
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/textwidth"
+)
+
+// FieldKind identifies the control a form Field renders.
+type FieldKind int
+
+const (
+	FieldText     FieldKind = iota // single-line Input
+	FieldTextArea                  // multi-line TextInput
+	FieldSelect                    // Select dropdown
+	FieldCheckbox                  // boolean toggle
+	FieldRadio                     // one choice among Options
+	FieldSwitch                    // on/off Switch
+)
+
+// Field is one labeled control in a Form. Only the state matching Kind
+// is used.
+type Field struct {
+	// Name identifies the field in FormValues and derives its node key
+	// ("<form Key>-<Name>").
+	Name  string
+	Label string
+	Kind  FieldKind
+
+	Input    Input     // FieldText
+	TextArea TextInput // FieldTextArea
+	Select   Select    // FieldSelect (Key is set by the form)
+	Checked  bool      // FieldCheckbox, FieldSwitch
+	Options  []string  // FieldRadio
+	Choice   int       // FieldRadio: index into Options
+
+	// Validate checks the field's typed value (string, bool, or Choice;
+	// see FormValues) on submit.
+	Validate func(value any) error
+	// Err is the field's current validation error, shown under it.
+	Err error
+}
+
+// Choice is the value of a select or radio field.
+type Choice struct {
+	Index  int
+	Option string
+}
+
+// FormValues holds submitted values by field name: string for text
+// fields, bool for checkboxes, and Choice for selects and radios.
+type FormValues map[string]any
+
+// String returns a text field's value, or a choice field's option.
+func (v FormValues) String(name string) string {
+	switch x := v[name].(type) {
+	case string:
+		return x
+	case Choice:
+		return x.Option
+	}
+	return ""
+}
+
+// Bool returns a checkbox field's value.
+func (v FormValues) Bool(name string) bool {
+	b, _ := v[name].(bool)
+	return b
+}
+
+// Index returns a choice field's selected index, or -1.
+func (v FormValues) Index(name string) int {
+	if c, ok := v[name].(Choice); ok {
+		return c.Index
+	}
+	return -1
+}
+
+// FieldError is a whole-form validation error attributed to one field.
+// Return it from Form.Validate to show the message under that field.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string { return e.Field + ": " + e.Err.Error() }
+func (e *FieldError) Unwrap() error { return e.Err }
+
+// FormSubmitMsg is emitted when a form passes validation on submit.
+type FormSubmitMsg struct {
+	Form   string // the form's Key
+	Values FormValues
+}
+
+// Form lays out labeled fields with a submit button, routes keys and
+// clicks to the focused field, validates, and emits FormSubmitMsg.
+//
+// Every field and the submit button are keyed and focusable, so Tab
+// cycles through them; Up/Down and Enter move between fields too.
+// Forward every message to Update (including FocusChangedMsg, which
+// tells the form what is focused). For a modal form, wrap Render in a
+// Box with WithFocusScope.
+type Form struct {
+	Key         string
+	Fields      []Field
+	SubmitLabel string // default "Submit"
+
+	// Validate checks the whole form on submit, after the per-field
+	// validators pass. Return a *FieldError to attach the error to a
+	// field; any other error is shown above the submit button.
+	Validate func(FormValues) error
+	Err      error
+
+	// Focused mirrors the focused node key (from FocusChangedMsg).
+	Focused string
+
+	LabelFG node.Color
+	FocusFG node.Color // label color of the focused field (default 6)
+	ErrorFG node.Color // default 1
+}
+
+// SubmitKey returns the node key of the submit button.
+func (f Form) SubmitKey() string { return f.Key + "-submit" }
+
+// FieldKey returns the node key of the named field.
+func (f Form) FieldKey(name string) string { return f.Key + "-" + name }
+
+// Values returns the current typed values of all fields.
+func (f Form) Values() FormValues {
+	v := make(FormValues, len(f.Fields))
+	for _, fld := range f.Fields {
+		v[fld.Name] = fld.value()
+	}
+	return v
+}
+
+func (fld Field) value() any {
+	switch fld.Kind {
+	case FieldText:
+		return fld.Input.Value
+	case FieldTextArea:
+		return fld.TextArea.Value
+	case FieldSelect:
+		return choice(fld.Select.Options, fld.Select.Selected)
+	case FieldCheckbox, FieldSwitch:
+		return fld.Checked
+	case FieldRadio:
+		return choice(fld.Options, fld.Choice)
+	}
+	return nil
+}
+
+func choice(opts []string, i int) Choice {
+	if i < 0 || i >= len(opts) {
+		return Choice{Index: -1}
+	}
+	return Choice{Index: i, Option: opts[i]}
+}
+
+// fieldAt returns the index of the field owning key (the field's own
+// key or a sub-key such as a select option), or -1. The longest
+// matching field key wins, so a field "name" doesn't claim the keys of
+// a field "name-first".
+func (f Form) fieldAt(key string) int {
+	found, longest := -1, 0
+	for i, fld := range f.Fields {
+		k := f.FieldKey(fld.Name)
+		if (key == k || strings.HasPrefix(key, k+"-")) && len(k) > longest {
+			found, longest = i, len(k)
+		}
+	}
+	return found
+}
+
+// Update routes a message to the form and returns the updated form and
+// an optional command (a focus move, or the FormSubmitMsg).
+func (f Form) Update(msg app.Msg) (Form, app.Cmd) {
+	f.Fields = append([]Field(nil), f.Fields...)
+	switch msg := msg.(type) {
+	case app.FocusChangedMsg:
+		f.Focused = msg.Key
+	case app.ClickMsg:
+		return f.click(msg.Key)
+	case app.PasteMsg:
+		if i := f.fieldAt(f.Focused); i >= 0 {
+			switch fld := &f.Fields[i]; fld.Kind {
+			case FieldText:
+				fld.Input = fld.Input.Paste(msg.Text)
+			case FieldTextArea:
+				fld.TextArea = fld.TextArea.Paste(msg.Text)
+			}
+		}
+	case app.KeyMsg:
+		return f.key(msg.Key)
+	}
+	return f, nil
+}
+
+func (f Form) click(key string) (Form, app.Cmd) {
+	if key == f.SubmitKey() {
+		return f.Submit()
+	}
+	i := f.fieldAt(key)
+	if i < 0 {
+		return f, nil
+	}
+	fld := &f.Fields[i]
+	fk := f.FieldKey(fld.Name)
+	fld.Err = nil
+	switch fld.Kind {
+	case FieldCheckbox, FieldSwitch:
+		fld.Checked = fld.checkbox(fk).Update(app.ClickMsg{Key: key}, f.Focused).Checked
+	case FieldRadio:
+		fld.Choice = fld.radio(fk).Update(app.ClickMsg{Key: key}, f.Focused).Selected
+	case FieldSelect:
+		if key == fk {
+			fld.Select.Open = !fld.Select.Open
+			fld.Select.HoverIndex = fld.Select.Selected
+		} else if idx, err := strconv.Atoi(strings.TrimPrefix(key, fk+"-")); err == nil && idx < len(fld.Select.Options) {
+			fld.Select.Selected = idx
+			fld.Select.Open = false
+			return f, app.RequestFocus(fk)
+		}
+	}
+	return f, nil
+}
+
+func (f Form) key(k input.Key) (Form, app.Cmd) {
+	if f.Focused == f.SubmitKey() {
+		switch k.Type {
+		case input.Enter:
+			return f.Submit()
+		case input.RuneKey:
+			if k.Rune == ' ' {
+				return f.Submit()
+			}
+		case input.Up:
+			return f, f.focusField(len(f.Fields) - 1)
+		}
+		return f, nil
+	}
+	i := f.fieldAt(f.Focused)
+	if i < 0 {
+		return f, nil
+	}
+	before := f.Fields[i].value()
+	f, cmd := f.fieldKey(i, k)
+	// Editing a field clears its error until the next submit; text
+	// fields keep showing their live validator result.
+	if fld := &f.Fields[i]; fld.value() != before {
+		fld.Err = nil
+		if fld.Kind == FieldText {
+			fld.Err = fld.Input.Err
+		}
+	}
+	return f, cmd
+}
+
+// fieldKey applies a key to field i.
+func (f Form) fieldKey(i int, k input.Key) (Form, app.Cmd) {
+	fld := &f.Fields[i]
+	space := k.Type == input.RuneKey && k.Rune == ' '
+
+	switch fld.Kind {
+	case FieldSelect:
+		s := &fld.Select
+		if s.Open {
+			switch {
+			case k.Type == input.Up && s.HoverIndex > 0:
+				s.HoverIndex--
+			case k.Type == input.Down && s.HoverIndex < len(s.Options)-1:
+				s.HoverIndex++
+			case k.Type == input.Enter || space:
+				s.Selected = s.HoverIndex
+				s.Open = false
+				return f, app.RequestFocus(f.FieldKey(fld.Name))
+			}
+			return f, nil
+		}
+		if k.Type == input.Enter || space {
+			s.Open = true
+			s.HoverIndex = s.Selected
+			return f, nil
+		}
+	case FieldCheckbox, FieldSwitch:
+		if k.Type == input.Enter || space {
+			fk := f.FieldKey(fld.Name)
+			fld.Checked = fld.checkbox(fk).Update(app.KeyMsg{Key: k}, fk).Checked
+			return f, nil
+		}
+	case FieldRadio:
+		// Up/Down stay with field navigation.
+		if k.Type != input.Up && k.Type != input.Down {
+			fk := f.FieldKey(fld.Name)
+			fld.Choice = fld.radio(fk).Update(app.KeyMsg{Key: k}, fk).Selected
+		}
+	case FieldText:
+		if k.Type != input.Enter && k.Type != input.Up && k.Type != input.Down {
+			fld.Input = fld.Input.Update(k)
+			return f, nil
+		}
+	case FieldTextArea:
+		if k.Type != input.Enter {
+			fld.TextArea = fld.TextArea.Update(k)
+			return f, nil
+		}
+	}
+
+	switch k.Type {
+	case input.Up:
+		return f, f.focusField(i - 1)
+	case input.Down:
+		return f, f.focusField(i + 1)
+	case input.Enter:
+		if i == len(f.Fields)-1 {
+			return f.Submit()
+		}
+		return f, f.focusField(i + 1)
+	}
+	return f, nil
+}
+
+// focusField returns a command focusing field i, or the submit button
+// past the last field.
+func (f Form) focusField(i int) app.Cmd {
+	switch {
+	case i < 0:
+		return nil
+	case i >= len(f.Fields):
+		return app.RequestFocus(f.SubmitKey())
+	}
+	return app.RequestFocus(f.FieldKey(f.Fields[i].Name))
+}
+
+// Submit validates every field and then the whole form. On success it
+// returns a command emitting FormSubmitMsg; otherwise errors are set
+// on the failing fields and focus moves to the first of them.
+func (f Form) Submit() (Form, app.Cmd) {
+	f.Fields = append([]Field(nil), f.Fields...)
+	f.Err = nil
+	firstBad := -1
+	for i := range f.Fields {
+		fld := &f.Fields[i]
+		fld.Err = nil
+		if fld.Kind == FieldText {
+			fld.Input.validate()
+			fld.Err = fld.Input.Err
+		}
+		if fld.Err == nil && fld.Validate != nil {
+			fld.Err = fld.Validate(fld.value())
+		}
+		if fld.Err != nil && firstBad < 0 {
+			firstBad = i
+		}
+	}
+	if firstBad < 0 && f.Validate != nil {
+		if err := f.Validate(f.Values()); err != nil {
+			var fe *FieldError
+			if errors.As(err, &fe) && f.fieldAt(f.FieldKey(fe.Field)) >= 0 {
+				firstBad = f.fieldAt(f.FieldKey(fe.Field))
+				f.Fields[firstBad].Err = fe.Err
+			} else {
+				f.Err = err
+			}
+		}
+	}
+	if firstBad >= 0 {
+		return f, f.focusField(firstBad)
+	}
+	if f.Err != nil {
+		return f, nil
+	}
+	values := f.Values()
+	key := f.Key
+	return f, func() app.Msg { return FormSubmitMsg{Form: key, Values: values} }
+}
+
+// Render builds the form as a Column: one row per field (label, then
+// control), errors under their fields, and the submit button.
+func (f Form) Render() node.Node {
+	focusFG, errFG := f.FocusFG, f.ErrorFG
+	if focusFG == 0 {
+		focusFG = 6
+	}
+	if errFG == 0 {
+		errFG = 1
+	}
+	labelW := 0
+	for _, fld := range f.Fields {
+		labelW = max(labelW, textwidth.String(fld.Label))
+	}
+
+	rows := make([]node.Node, 0, len(f.Fields)*2+2)
+	for i, fld := range f.Fields {
+		key := f.FieldKey(fld.Name)
+		focused := f.fieldAt(f.Focused) == i
+		labelFG, labelStyle := f.LabelFG, node.StyleFlags(0)
+		if focused {
+			labelFG, labelStyle = focusFG, node.Bold
+		}
+		label := fld.Label + strings.Repeat(" ", labelW-textwidth.String(fld.Label)) + "  "
+		rows = append(rows, node.Row(
+			node.TextStyled(label, labelFG, 0, labelStyle),
+			fld.render(key, focused),
+		))
+		if fld.Err != nil {
+			pad := strings.Repeat(" ", labelW+2)
+			rows = append(rows, node.TextStyled(pad+fld.Err.Error(), errFG, 0, 0))
+		}
+	}
+	if f.Err != nil {
+		rows = append(rows, node.TextStyled(f.Err.Error(), errFG, 0, 0))
+	}
+
+	label := f.SubmitLabel
+	if label == "" {
+		label = "Submit"
+	}
+	style := node.StyleFlags(0)
+	if f.Focused == f.SubmitKey() {
+		style = node.Reverse | node.Bold
+	}
+	rows = append(rows,
+		node.Text(""),
+		node.TextStyled("[ "+label+" ]", 0, 0, style).WithKey(f.SubmitKey()).WithFocusable(),
+	)
+	return node.Column(rows...)
+}
+
+// render builds the field's control, keyed and focusable as key.
+func (fld Field) render(key string, focused bool) node.Node {
+	switch fld.Kind {
+	case FieldText:
+		in := fld.Input
+		in.Focused = focused
+		in.Err = nil // shown by the form under the row
+		return in.Render("", 0, 0).WithKey(key).WithFocusable()
+	case FieldTextArea:
+		ta := fld.TextArea
+		ta.Focused = focused
+		return ta.Render("", 0, 0, 0).WithKey(key).WithFocusable()
+	case FieldSelect:
+		s := fld.Select
+		s.Key = key
+		return s.Render()
+	case FieldCheckbox:
+		return fld.checkbox(key).Render(focusedKey(key, focused))
+	case FieldSwitch:
+		return Switch{Key: key, On: fld.Checked}.Render(focusedKey(key, focused))
+	case FieldRadio:
+		return fld.radio(key).Render(focusedKey(key, focused))
+	}
+	return node.Text("")
+}
+
+// checkbox returns the field's boolean state as a Checkbox keyed key.
+func (fld Field) checkbox(key string) Checkbox {
+	return Checkbox{Key: key, Checked: fld.Checked}
+}
+
+// radio returns the field's choice state as a horizontal RadioGroup
+// keyed key.
+func (fld Field) radio(key string) RadioGroup {
+	return RadioGroup{Key: key, Options: fld.Options, Selected: fld.Choice, Horizontal: true}
+}
+
+func focusedKey(key string, focused bool) string {
+	if focused {
+		return key
+	}
+	return ""
+}
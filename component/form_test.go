@@ -0,0 +1,139 @@
+package component
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/tooeytest"
+)
+
+func testForm() Form {
+	return Form{
+		Key: "f",
+		Fields: []Field{
+			{Name: "name", Label: "Name", Kind: FieldText, Input: Input{Placeholder: "you"},
+				Validate: func(v any) error {
+					if v.(string) == "" {
+						return errors.New("required")
+					}
+					return nil
+				}},
+			{Name: "news", Label: "Newsletter", Kind: FieldCheckbox},
+			{Name: "size", Label: "Size", Kind: FieldRadio, Options: []string{"S", "M"}},
+		},
+	}
+}
+
+func TestFormRender(t *testing.T) {
+	f := testForm()
+	tooeytest.AssertFrame(t, f.Render(), 30, 6, `
+		Name        you
+		Newsletter  [ ]
+		Size        (•) S  ( ) M
+
+		[ Submit ]`)
+}
+
+func TestFormFieldValidation(t *testing.T) {
+	f := testForm()
+	f, cmd := f.Submit()
+	if f.Fields[0].Err == nil {
+		t.Fatal("empty required field should fail validation")
+	}
+	if msg := cmd(); msg != nil {
+		if _, ok := msg.(FormSubmitMsg); ok {
+			t.Fatal("invalid form must not submit")
+		}
+	}
+	got := tooeytest.RenderText(f.Render(), 30, 6)
+	if want := "            required"; !containsLine(got, want) {
+		t.Fatalf("error not shown under field:\n%s", got)
+	}
+
+	// Typing clears the error.
+	f, _ = f.Update(app.FocusChangedMsg{Key: "f-name"})
+	f, _ = f.Update(app.KeyMsg{Key: input.Key{Type: input.RuneKey, Rune: 'a'}})
+	if f.Fields[0].Err != nil {
+		t.Fatalf("edit should clear error, got %v", f.Fields[0].Err)
+	}
+}
+
+func TestFormWholeValidatorFieldError(t *testing.T) {
+	f := testForm()
+	f.Fields[0].Input = f.Fields[0].Input.SetValue("bob")
+	f.Validate = func(v FormValues) error {
+		if !v.Bool("news") {
+			return &FieldError{Field: "news", Err: errors.New("must subscribe")}
+		}
+		return nil
+	}
+	f, _ = f.Submit()
+	if f.Fields[1].Err == nil || f.Fields[1].Err.Error() != "must subscribe" {
+		t.Fatalf("field error not attached: %v", f.Fields[1].Err)
+	}
+	if f.Err != nil {
+		t.Fatalf("form error should be empty, got %v", f.Err)
+	}
+}
+
+func TestFormSubmitValues(t *testing.T) {
+	f := testForm()
+	f.Fields[0].Input = f.Fields[0].Input.SetValue("bob")
+	f, _ = f.Update(app.ClickMsg{Key: "f-news"})
+	f, _ = f.Update(app.ClickMsg{Key: "f-size-1"})
+	f, _ = f.Update(app.FocusChangedMsg{Key: f.SubmitKey()})
+	_, cmd := f.Update(app.KeyMsg{Key: input.Key{Type: input.Enter}})
+	if cmd == nil {
+		t.Fatal("expected submit command")
+	}
+	msg, ok := cmd().(FormSubmitMsg)
+	if !ok {
+		t.Fatalf("expected FormSubmitMsg, got %T", cmd())
+	}
+	v := msg.Values
+	if msg.Form != "f" || v.String("name") != "bob" || !v.Bool("news") || v.Index("size") != 1 || v.String("size") != "M" {
+		t.Fatalf("unexpected values: %+v", msg)
+	}
+}
+
+func TestFormKeyboardToggles(t *testing.T) {
+	f := testForm()
+	f, _ = f.Update(app.FocusChangedMsg{Key: "f-news"})
+	f, _ = f.Update(app.KeyMsg{Key: input.Key{Type: input.RuneKey, Rune: ' '}})
+	if !f.Fields[1].Checked {
+		t.Fatal("space should toggle checkbox")
+	}
+	f, _ = f.Update(app.FocusChangedMsg{Key: "f-size"})
+	f, _ = f.Update(app.KeyMsg{Key: input.Key{Type: input.Right}})
+	if f.Fields[2].Choice != 1 {
+		t.Fatal("right should move radio choice")
+	}
+}
+
+func TestFormPrefixedFieldNames(t *testing.T) {
+	f := Form{Key: "f", Fields: []Field{
+		{Name: "name", Kind: FieldText},
+		{Name: "name-first", Kind: FieldText},
+	}}
+	f, _ = f.Update(app.FocusChangedMsg{Key: "f-name-first"})
+	f, _ = f.Update(app.KeyMsg{Key: input.Key{Type: input.RuneKey, Rune: 'a'}})
+	f, _ = f.Update(app.PasteMsg{Text: "da"})
+	if got := f.Fields[1].Input.Value; got != "ada" {
+		t.Fatalf("name-first = %q, want %q", got, "ada")
+	}
+	if got := f.Fields[0].Input.Value; got != "" {
+		t.Fatalf("name = %q, want it untouched", got)
+	}
+}
+
+func containsLine(frame, want string) bool {
+	for _, l := range strings.Split(frame, "\n") {
+		if l == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,365 @@
+package component
+
+import (
+	"strings"
+
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/textwidth"
+)
+
+// EchoMode controls how an Input displays its value.
+type EchoMode int
+
+const (
+	// EchoNormal shows the value as typed.
+	EchoNormal EchoMode = iota
+	// EchoPassword shows one mask rune per character.
+	EchoPassword
+	// EchoNone shows nothing; the cursor stays at the start.
+	EchoNone
+)
+
+// Input holds state for a single-line text input. Unlike TextInput it
+// never wraps: when the value is wider than Width the view scrolls
+// horizontally to keep the cursor visible.
+type Input struct {
+	Value       string
+	Cursor      int // rune offset into Value
+	Placeholder string
+	Focused     bool
+
+	// Width is the display width of the editable area, excluding the
+	// render prefix. 0 disables horizontal scrolling.
+	Width int
+	// Offset is the rune offset of the first visible character.
+	Offset int
+
+	// CharLimit caps the value's length in runes (0 = unlimited).
+	CharLimit int
+
+	Echo     EchoMode
+	MaskRune rune // used by EchoPassword (default '•')
+
+	// Validate is run after every edit; its result is kept in Err.
+	Validate func(string) error
+	Err      error
+
+	// History holds previously submitted values, oldest first. Up and
+	// Down recall entries; Submit appends to it.
+	History []string
+
+	// Suggestions are completion candidates. The first one extending
+	// the current value is shown as dim ghost text after the cursor and
+	// accepted with Right at the end of the value.
+	Suggestions []string
+
+	histIdx int    // 0 = editing the draft, n = n-th most recent entry
+	draft   string // value being edited before history browsing began
+}
+
+// NewInput creates a focused single-line input with a placeholder.
+func NewInput(placeholder string) Input {
+	return Input{Placeholder: placeholder, Focused: true}
+}
+
+// Update handles a key event and returns the updated Input.
+func (in Input) Update(key input.Key) Input {
+	runes := []rune(in.Value)
+	in.Cursor = clampInt(in.Cursor, 0, len(runes))
+	edited := false
+
+	switch key.Type {
+	case input.RuneKey:
+		if in.CharLimit > 0 && len(runes) >= in.CharLimit {
+			break
+		}
+		runes = append(runes[:in.Cursor], append([]rune{key.Rune}, runes[in.Cursor:]...)...)
+		in.Cursor++
+		edited = true
+	case input.Backspace:
+		if in.Cursor > 0 {
+			runes = append(runes[:in.Cursor-1], runes[in.Cursor:]...)
+			in.Cursor--
+			edited = true
+		}
+	case input.Delete:
+		if in.Cursor < len(runes) {
+			runes = append(runes[:in.Cursor], runes[in.Cursor+1:]...)
+			edited = true
+		}
+	case input.Left:
+		if in.Cursor > 0 {
+			in.Cursor--
+		}
+	case input.Right:
+		if in.Cursor < len(runes) {
+			in.Cursor++
+		} else if s := in.Suggestion(); s != "" {
+			runes = []rune(in.limit(s))
+			in.Cursor = len(runes)
+			edited = true
+		}
+	case input.Home:
+		in.Cursor = 0
+	case input.End:
+		in.Cursor = len(runes)
+	case input.AltLeft:
+		in.Cursor = wordLeft(runes, in.Cursor)
+	case input.AltRight:
+		in.Cursor = wordRight(runes, in.Cursor)
+	case input.Up:
+		return in.recall(in.histIdx + 1)
+	case input.Down:
+		return in.recall(in.histIdx - 1)
+	}
+
+	in.Value = string(runes)
+	if edited {
+		in.histIdx = 0
+		in.validate()
+	}
+	in.Offset = in.scrollOffset()
+	return in
+}
+
+// Paste inserts text at the cursor. Newlines become spaces, and the
+// text is cut to fit CharLimit.
+func (in Input) Paste(text string) Input {
+	runes := []rune(in.Value)
+	in.Cursor = clampInt(in.Cursor, 0, len(runes))
+	paste := []rune(strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(text))
+	if in.CharLimit > 0 {
+		room := in.CharLimit - len(runes)
+		if room < 0 {
+			room = 0
+		}
+		if len(paste) > room {
+			paste = paste[:room]
+		}
+	}
+	out := make([]rune, 0, len(runes)+len(paste))
+	out = append(out, runes[:in.Cursor]...)
+	out = append(out, paste...)
+	out = append(out, runes[in.Cursor:]...)
+	in.Value = string(out)
+	in.Cursor += len(paste)
+	in.histIdx = 0
+	in.validate()
+	in.Offset = in.scrollOffset()
+	return in
+}
+
+// SetValue replaces the value, moving the cursor to the end.
+func (in Input) SetValue(s string) Input {
+	in.Value = in.limit(s)
+	in.Cursor = len([]rune(in.Value))
+	in.histIdx = 0
+	in.validate()
+	in.Offset = in.scrollOffset()
+	return in
+}
+
+// Submit validates the value. If it is valid, Submit returns it, adds
+// it to History, and resets the input; otherwise it returns the
+// validation error and leaves the input unchanged (with Err set).
+func (in Input) Submit() (string, Input, error) {
+	in.validate()
+	if in.Err != nil {
+		return "", in, in.Err
+	}
+	val := in.Value
+	if val != "" && (len(in.History) == 0 || in.History[len(in.History)-1] != val) {
+		in.History = append(in.History, val)
+	}
+	in.Value = ""
+	in.Cursor = 0
+	in.Offset = 0
+	in.histIdx = 0
+	in.draft = ""
+	return val, in, nil
+}
+
+// Valid reports whether the current value passes Validate.
+func (in Input) Valid() bool {
+	return in.Validate == nil || in.Validate(in.Value) == nil
+}
+
+// Suggestion returns the first suggestion that extends the current
+// value, or "" if none does.
+func (in Input) Suggestion() string {
+	if in.Value == "" || in.Echo != EchoNormal {
+		return ""
+	}
+	for _, s := range in.Suggestions {
+		if len(s) > len(in.Value) && strings.HasPrefix(s, in.Value) {
+			return s
+		}
+	}
+	return ""
+}
+
+// recall moves to the idx-th most recent history entry (0 restores the
+// draft being edited when browsing began).
+func (in Input) recall(idx int) Input {
+	if idx < 0 || idx > len(in.History) || idx == in.histIdx {
+		return in
+	}
+	if in.histIdx == 0 {
+		in.draft = in.Value
+	}
+	in.histIdx = idx
+	if idx == 0 {
+		in.Value = in.draft
+	} else {
+		in.Value = in.History[len(in.History)-idx]
+	}
+	in.Value = in.limit(in.Value)
+	in.Cursor = len([]rune(in.Value))
+	in.validate()
+	in.Offset = in.scrollOffset()
+	return in
+}
+
+func (in *Input) validate() {
+	in.Err = nil
+	if in.Validate != nil {
+		in.Err = in.Validate(in.Value)
+	}
+}
+
+// limit cuts s to CharLimit runes.
+func (in Input) limit(s string) string {
+	if in.CharLimit > 0 {
+		if r := []rune(s); len(r) > in.CharLimit {
+			return string(r[:in.CharLimit])
+		}
+	}
+	return s
+}
+
+// display returns the runes shown for the value under the echo mode.
+func (in Input) display() []rune {
+	switch in.Echo {
+	case EchoPassword:
+		mask := in.MaskRune
+		if mask == 0 {
+			mask = '•'
+		}
+		return []rune(strings.Repeat(string(mask), len([]rune(in.Value))))
+	case EchoNone:
+		return nil
+	default:
+		return []rune(in.Value)
+	}
+}
+
+// scrollOffset returns the first visible rune so that the cursor (and
+// the cell it occupies) fits within Width, moving the view as little as
+// possible from the current Offset.
+func (in Input) scrollOffset() int {
+	runes := in.display()
+	cursor := clampInt(in.Cursor, 0, len(runes))
+	if in.Width <= 0 {
+		return 0
+	}
+	// The cursor occupies the cell of the rune under it, or one blank
+	// cell when parked at the end.
+	cw := 1
+	if cursor < len(runes) {
+		cw = max(1, textwidth.Rune(runes[cursor]))
+	}
+	off := clampInt(in.Offset, 0, cursor)
+	for off < cursor && textwidth.String(string(runes[off:cursor]))+cw > in.Width {
+		off++
+	}
+	// Pull the view back when text was deleted and there is room on
+	// the left again.
+	for off > 0 && textwidth.String(string(runes[off-1:]))+1 <= in.Width {
+		off--
+	}
+	return off
+}
+
+// Render returns a single-line node showing the visible slice of the
+// value with a block cursor and any suggestion ghost text. When Err is
+// set, the error message is shown on a second line.
+func (in Input) Render(prefix string, fg, bg node.Color) node.Node {
+	line := in.renderLine(prefix, fg, bg)
+	if in.Err == nil {
+		return line
+	}
+	pad := strings.Repeat(" ", textwidth.String(prefix))
+	return node.Column(line, node.TextStyled(pad+in.Err.Error(), node.Color(1), bg, 0))
+}
+
+func (in Input) renderLine(prefix string, fg, bg node.Color) node.Node {
+	if in.Value == "" {
+		if in.Focused {
+			return node.Row(
+				node.TextStyled(prefix, fg, bg, 0).WithNoWrap(),
+				node.TextStyled(" ", node.Color(0), node.Color(15), 0), // block cursor
+				node.TextStyled(in.Placeholder, node.Color(8), bg, node.Dim).WithNoWrap(),
+			)
+		}
+		return node.TextStyled(prefix+in.Placeholder, node.Color(8), bg, node.Dim).WithNoWrap()
+	}
+
+	runes := in.display()
+	cursor := clampInt(in.Cursor, 0, len(runes))
+	off := in.scrollOffset()
+	end := len(runes)
+	if in.Width > 0 {
+		// scrollOffset guarantees the cursor fits, so take as much of
+		// the rest as the width allows.
+		w := 0
+		for end = off; end < len(runes); end++ {
+			w += textwidth.Rune(runes[end])
+			if w > in.Width {
+				break
+			}
+		}
+	}
+
+	if !in.Focused {
+		return node.TextStyled(prefix+string(runes[off:end]), fg, bg, 0).WithNoWrap()
+	}
+
+	before := string(runes[off:cursor])
+	cursorChar, after := " ", ""
+	if cursor < end {
+		cursorChar = string(runes[cursor])
+		after = string(runes[cursor+1 : end])
+	}
+	// At the end, a suggestion's remainder shows as ghost text starting
+	// under the cursor.
+	ghost := ""
+	if s := in.Suggestion(); s != "" && cursor == len(runes) {
+		ghost = strings.TrimPrefix(s, in.Value)
+		if in.Width > 0 {
+			ghost = textwidth.Truncate(ghost, in.Width-textwidth.String(before))
+		}
+		if g := []rune(ghost); len(g) > 0 {
+			cursorChar, ghost = string(g[0]), string(g[1:])
+		}
+	}
+	children := []node.Node{
+		node.TextStyled(prefix+before, fg, bg, 0).WithNoWrap(),
+		node.TextStyled(cursorChar, node.Color(0), node.Color(15), 0),
+		node.TextStyled(after, fg, bg, 0).WithNoWrap(),
+	}
+	if ghost != "" {
+		children = append(children, node.TextStyled(ghost, node.Color(8), bg, node.Dim).WithNoWrap())
+	}
+	return node.Row(children...)
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,128 @@
+package component
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/tooeytest"
+)
+
+func typeString(in Input, s string) Input {
+	for _, r := range s {
+		in = in.Update(input.Key{Type: input.RuneKey, Rune: r})
+	}
+	return in
+}
+
+func TestInputScrollsToCursor(t *testing.T) {
+	in := NewInput("")
+	in.Width = 5
+	in = typeString(in, "abcdefgh")
+	if got := tooeytest.RenderText(in.Render("", 0, 0), 10, 1); got != "efgh" {
+		t.Fatalf("view should follow the cursor, got %q", got)
+	}
+	in = in.Update(input.Key{Type: input.Home})
+	if in.Offset != 0 {
+		t.Fatalf("Home should scroll back to the start, offset %d", in.Offset)
+	}
+	if got := tooeytest.RenderText(in.Render("> ", 0, 0), 10, 1); got != "> abcde" {
+		t.Fatalf("unexpected view at start: %q", got)
+	}
+}
+
+func TestInputEchoModes(t *testing.T) {
+	in := typeString(NewInput(""), "secret")
+	in.Echo = EchoPassword
+	if got := tooeytest.RenderText(in.Render("", 0, 0), 10, 1); got != "••••••" {
+		t.Fatalf("password echo should mask, got %q", got)
+	}
+	in.Echo = EchoNone
+	if got := tooeytest.RenderText(in.Render("", 0, 0), 10, 1); got != "" {
+		t.Fatalf("no-echo should hide the value, got %q", got)
+	}
+	if in.Value != "secret" {
+		t.Fatalf("echo mode must not change the value, got %q", in.Value)
+	}
+}
+
+func TestInputCharLimit(t *testing.T) {
+	in := NewInput("")
+	in.CharLimit = 3
+	in = typeString(in, "abcdef")
+	if in.Value != "abc" {
+		t.Fatalf("typing should stop at the limit, got %q", in.Value)
+	}
+	in = NewInput("")
+	in.CharLimit = 4
+	if in = in.Paste("hello\nworld"); in.Value != "hell" {
+		t.Fatalf("paste should be cut to the limit, got %q", in.Value)
+	}
+}
+
+func TestInputValidation(t *testing.T) {
+	in := NewInput("")
+	in.Validate = func(s string) error {
+		if len(s) < 3 {
+			return errors.New("too short")
+		}
+		return nil
+	}
+	in = typeString(in, "ab")
+	if in.Err == nil {
+		t.Fatal("validator should run after edits")
+	}
+	if got := tooeytest.RenderText(in.Render("> ", 0, 0), 20, 2); got != "> ab\n  too short" {
+		t.Fatalf("error should render under the field, got %q", got)
+	}
+	if _, _, err := in.Submit(); err == nil {
+		t.Fatal("submit should refuse an invalid value")
+	}
+	in = typeString(in, "c")
+	val, in, err := in.Submit()
+	if err != nil || val != "abc" || in.Value != "" {
+		t.Fatalf("valid submit should return and reset, got %q %q %v", val, in.Value, err)
+	}
+}
+
+func TestInputHistory(t *testing.T) {
+	in := NewInput("")
+	for _, s := range []string{"one", "two"} {
+		in = typeString(in, s)
+		_, in, _ = in.Submit()
+	}
+	in = typeString(in, "dra")
+	in = in.Update(input.Key{Type: input.Up})
+	if in.Value != "two" {
+		t.Fatalf("Up should recall the latest entry, got %q", in.Value)
+	}
+	in = in.Update(input.Key{Type: input.Up})
+	in = in.Update(input.Key{Type: input.Up})
+	if in.Value != "one" {
+		t.Fatalf("Up should stop at the oldest entry, got %q", in.Value)
+	}
+	in = in.Update(input.Key{Type: input.Down})
+	in = in.Update(input.Key{Type: input.Down})
+	if in.Value != "dra" {
+		t.Fatalf("Down past the newest entry should restore the draft, got %q", in.Value)
+	}
+}
+
+func TestInputSuggestion(t *testing.T) {
+	in := NewInput("")
+	in.Suggestions = []string{"apple", "apricot"}
+	in = typeString(in, "apr")
+	if got := tooeytest.RenderText(in.Render("", 0, 0), 20, 1); got != "apricot" {
+		t.Fatalf("ghost text should start under the cursor, got %q", got)
+	}
+	// The ghost's first rune sits in the cursor cell.
+	buf := tooeytest.Render(in.Render("", 0, 0), 20, 1)
+	if c := buf.Get(3, 0); c.Rune != 'i' || c.BG != node.Color(15) {
+		t.Fatalf("cursor cell = %+v, want 'i' on the cursor background", c)
+	}
+	in = in.Update(input.Key{Type: input.Right})
+	if in.Value != "apricot" || in.Cursor != 7 {
+		t.Fatalf("Right at the end should accept the suggestion, got %q@%d", in.Value, in.Cursor)
+	}
+}
@@ -0,0 +1,320 @@
+package component
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/textwidth"
+)
+
+// MenuItem is an entry in a Menu. An item with Items opens a submenu to
+// the side instead of activating.
+type MenuItem struct {
+	// ID identifies the item in MenuActivateMsg and derives its node
+	// key ("<menu Key>-<ID>"); IDs must be unique within a menu.
+	ID       string
+	Label    string
+	Shortcut string // hint shown right-aligned, e.g. "Ctrl+S"
+	// Accel is the accelerator key, underlined in the label. Pressing
+	// it while the menu is open picks the item.
+	Accel     rune
+	Disabled  bool
+	Separator bool // a divider line; other fields are ignored
+	Items     []MenuItem
+}
+
+// selectable reports whether the item can be hovered.
+func (it MenuItem) selectable() bool {
+	return !it.Separator && !it.Disabled
+}
+
+// MenuActivateMsg is emitted when a menu item is chosen.
+type MenuActivateMsg struct {
+	Menu string // the menu's Key
+	ID   string
+}
+
+// Menu is a popup menu opened at a screen position, e.g. from a right
+// click. Render returns an overlay layer that traps focus with
+// WithFocusScope; Escape (DismissMsg) or a click outside closes it.
+//
+// Navigate with Up/Down, open submenus with Right or Enter, close them
+// with Left, and pick items with Enter, their accelerator, or a click.
+// Choosing an item returns a Cmd emitting MenuActivateMsg.
+type Menu struct {
+	Key   string
+	Items []MenuItem
+	X, Y  int
+	Open  bool
+
+	FG         node.Color
+	BG         node.Color // default 236
+	HoverFG    node.Color // default 0
+	HoverBG    node.Color // default 6
+	DisabledFG node.Color // default 8
+
+	// path holds the hovered index at each open level; len(path) is
+	// the number of open panels.
+	path []int
+}
+
+// Show opens the menu at (x, y) with the first item hovered.
+func (m Menu) Show(x, y int) Menu {
+	m.X, m.Y, m.Open = x, y, true
+	m.path = []int{nextSelectable(m.Items, -1, 1)}
+	return m
+}
+
+// Close closes the menu and any open submenus.
+func (m Menu) Close() Menu {
+	m.Open = false
+	m.path = nil
+	return m
+}
+
+// level returns the items shown by open panel i.
+func (m Menu) level(i int) []MenuItem {
+	items := m.Items
+	for _, idx := range m.path[:i] {
+		if idx < 0 || idx >= len(items) {
+			return nil
+		}
+		items = items[idx].Items
+	}
+	return items
+}
+
+// hovered returns the hovered item of the deepest open panel.
+func (m Menu) hovered() (MenuItem, bool) {
+	if len(m.path) == 0 {
+		return MenuItem{}, false
+	}
+	items := m.level(len(m.path) - 1)
+	idx := m.path[len(m.path)-1]
+	if idx < 0 || idx >= len(items) {
+		return MenuItem{}, false
+	}
+	return items[idx], true
+}
+
+// nextSelectable returns the next selectable index after from in
+// direction dir (wrapping), or -1 if there is none.
+func nextSelectable(items []MenuItem, from, dir int) int {
+	n := len(items)
+	for i := 1; i <= n; i++ {
+		j := ((from+dir*i)%n + n) % n
+		if items[j].selectable() {
+			return j
+		}
+	}
+	return -1
+}
+
+// findPath returns the index path to the item with the given ID.
+func findPath(items []MenuItem, id string) []int {
+	for i, it := range items {
+		if it.ID == id && !it.Separator {
+			return []int{i}
+		}
+		if sub := findPath(it.Items, id); sub != nil {
+			return append([]int{i}, sub...)
+		}
+	}
+	return nil
+}
+
+// Update handles keys and clicks while the menu is open. The returned
+// Cmd emits MenuActivateMsg when an item is chosen.
+func (m Menu) Update(msg app.Msg) (Menu, app.Cmd) {
+	if !m.Open {
+		return m, nil
+	}
+	switch msg := msg.(type) {
+	case app.DismissMsg:
+		if msg.Scope == m.Key {
+			return m.back(), nil
+		}
+	case app.ClickMsg:
+		id, ok := strings.CutPrefix(msg.Key, m.Key+"-")
+		path := findPath(m.Items, id)
+		if !ok || path == nil {
+			// Outside the menu panels.
+			return m.Close(), nil
+		}
+		m.path = path
+		return m.choose()
+	case app.KeyMsg:
+		return m.key(msg.Key)
+	}
+	return m, nil
+}
+
+// back closes the deepest submenu, or the whole menu at the top level.
+func (m Menu) back() Menu {
+	if len(m.path) > 1 {
+		m.path = m.path[:len(m.path)-1]
+		return m
+	}
+	return m.Close()
+}
+
+func (m Menu) key(k input.Key) (Menu, app.Cmd) {
+	if len(m.path) == 0 {
+		m.path = []int{nextSelectable(m.Items, -1, 1)}
+	}
+	depth := len(m.path) - 1
+	items := m.level(depth)
+	switch k.Type {
+	case input.Up, input.Down:
+		dir := 1
+		if k.Type == input.Up {
+			dir = -1
+		}
+		m.path = append(append([]int(nil), m.path[:depth]...), nextSelectable(items, m.path[depth], dir))
+	case input.Right:
+		if it, ok := m.hovered(); ok && it.selectable() && len(it.Items) > 0 {
+			m.path = append(append([]int(nil), m.path...), nextSelectable(it.Items, -1, 1))
+		}
+	case input.Left:
+		if depth > 0 {
+			m.path = m.path[:depth]
+		}
+	case input.Escape:
+		return m.back(), nil
+	case input.Enter:
+		return m.choose()
+	case input.RuneKey:
+		if k.Rune == ' ' {
+			return m.choose()
+		}
+		for i, it := range items {
+			if it.selectable() && it.Accel != 0 && unicode.ToLower(it.Accel) == unicode.ToLower(k.Rune) {
+				m.path = append(append([]int(nil), m.path[:depth]...), i)
+				return m.choose()
+			}
+		}
+	}
+	return m, nil
+}
+
+// choose opens the hovered item's submenu or activates it.
+func (m Menu) choose() (Menu, app.Cmd) {
+	it, ok := m.hovered()
+	if !ok || !it.selectable() {
+		return m, nil
+	}
+	if len(it.Items) > 0 {
+		m.path = append(append([]int(nil), m.path...), nextSelectable(it.Items, -1, 1))
+		return m, nil
+	}
+	key, id := m.Key, it.ID
+	return m.Close(), func() app.Msg { return MenuActivateMsg{Menu: key, ID: id} }
+}
+
+// Render returns the menu as an overlay layer (place it last in a
+// node.Overlay), or an empty pass-through node when closed. Open
+// submenus appear to the right of their parent item.
+func (m Menu) Render() node.Node {
+	if !m.Open {
+		return node.Column().WithPassThrough()
+	}
+	path := m.path
+	if len(path) == 0 {
+		path = []int{-1}
+	}
+	// Each panel is a column of fixed width whose top spacer drops it
+	// to its row; side by side they cascade to the right.
+	var cols []node.Node
+	if m.X > 0 {
+		cols = append(cols, node.Row().WithSize(m.X, 0))
+	}
+	y := m.Y
+	for i := range path {
+		items := m.level(i)
+		if len(items) == 0 {
+			break
+		}
+		panel, w := m.renderPanel(items, path[i])
+		col := node.Column(panel)
+		if y > 0 {
+			col = node.Column(node.Column().WithSize(0, y), panel)
+		}
+		cols = append(cols, col.WithSize(w, 0))
+		// Line the submenu's first item up with its parent item.
+		y += max(path[i], 0)
+	}
+	return node.Row(cols...).WithKey(m.Key).WithFocusScope().WithFocusable()
+}
+
+// renderPanel builds one bordered panel and returns it with its width.
+func (m Menu) renderPanel(items []MenuItem, hover int) (node.Node, int) {
+	bg, hoverFG, hoverBG, disFG := m.BG, m.HoverFG, m.HoverBG, m.DisabledFG
+	if bg == 0 {
+		bg = 236
+	}
+	if hoverBG == 0 {
+		hoverBG = 6
+	}
+	if disFG == 0 {
+		disFG = 8
+	}
+
+	// The right-hand column holds either a shortcut or a submenu arrow.
+	labelW, rightW := 0, 0
+	for _, it := range items {
+		labelW = max(labelW, textwidth.String(it.Label))
+		rightW = max(rightW, textwidth.String(it.Shortcut))
+		if len(it.Items) > 0 {
+			rightW = max(rightW, 1)
+		}
+	}
+	inner := 1 + labelW + 1
+	if rightW > 0 {
+		inner += 2 + rightW
+	}
+
+	rows := make([]node.Node, 0, len(items))
+	for i, it := range items {
+		if it.Separator {
+			rows = append(rows, node.TextStyled(strings.Repeat("─", inner), disFG, bg, 0))
+			continue
+		}
+		fg, rowBG := m.FG, bg
+		switch {
+		case it.Disabled:
+			fg = disFG
+		case i == hover:
+			fg, rowBG = hoverFG, hoverBG
+		}
+		right := it.Shortcut
+		if len(it.Items) > 0 {
+			right = "▸"
+		}
+		pad := inner - 2 - textwidth.String(it.Label) - textwidth.String(right)
+		tail := strings.Repeat(" ", max(pad, 1)) + right + " "
+		row := node.Row(append(accelLabel(" ", it.Label, it.Accel, fg, rowBG),
+			node.TextStyled(tail, fg, rowBG, 0))...)
+		rows = append(rows, row.WithKey(m.Key+"-"+it.ID))
+	}
+	panel := node.Box(node.BorderSingle, node.Column(rows...)).WithFG(m.FG).WithBG(bg)
+	return panel, inner + 2
+}
+
+// accelLabel renders prefix+label with the first occurrence of accel
+// underlined.
+func accelLabel(prefix, label string, accel rune, fg, bg node.Color) []node.Node {
+	runes := []rune(label)
+	for i, r := range runes {
+		if accel != 0 && unicode.ToLower(r) == unicode.ToLower(accel) {
+			return []node.Node{
+				node.TextStyled(prefix+string(runes[:i]), fg, bg, 0).WithNoWrap(),
+				node.TextStyled(string(r), fg, bg, node.Underline),
+				node.TextStyled(string(runes[i+1:]), fg, bg, 0).WithNoWrap(),
+			}
+		}
+	}
+	return []node.Node{node.TextStyled(prefix+label, fg, bg, 0).WithNoWrap()}
+}
@@ -0,0 +1,130 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/tooeytest"
+)
+
+func testMenu() Menu {
+	return Menu{Key: "ctx", Items: []MenuItem{
+		{ID: "copy", Label: "Copy", Shortcut: "^C", Accel: 'c'},
+		{ID: "paste", Label: "Paste", Disabled: true},
+		{Separator: true},
+		{ID: "sort", Label: "Sort", Accel: 's', Items: []MenuItem{
+			{ID: "asc", Label: "Asc"},
+			{ID: "desc", Label: "Desc"},
+		}},
+	}}
+}
+
+func menuKey(m Menu, typ input.KeyType) (Menu, app.Cmd) {
+	return m.Update(app.KeyMsg{Key: input.Key{Type: typ}})
+}
+
+func TestMenuRenderAtPosition(t *testing.T) {
+	m := testMenu().Show(2, 1)
+	got := tooeytest.RenderText(node.Overlay(node.Text(""), m.Render()), 16, 7)
+	want := "\n" +
+		"  ┌───────────┐\n" +
+		"  │ Copy   ^C │\n" +
+		"  │ Paste     │\n" +
+		"  │───────────│\n" +
+		"  │ Sort    ▸ │\n" +
+		"  └───────────┘"
+	if got != want {
+		t.Fatalf("menu frame:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMenuKeyboardSkipsDisabledAndOpensSubmenu(t *testing.T) {
+	m := testMenu().Show(0, 0)
+	m, _ = menuKey(m, input.Down)
+	if it, _ := m.hovered(); it.ID != "sort" {
+		t.Fatalf("down should skip disabled and separator, hovered %q", it.ID)
+	}
+	m, _ = menuKey(m, input.Right)
+	m, _ = menuKey(m, input.Down)
+	if it, _ := m.hovered(); it.ID != "desc" {
+		t.Fatalf("submenu hover = %q, want desc", it.ID)
+	}
+	got := tooeytest.RenderText(node.Overlay(node.Text(""), m.Render()), 24, 7)
+	want := "┌───────────┐\n" +
+		"│ Copy   ^C │\n" +
+		"│ Paste     │\n" +
+		"│───────────│┌──────┐\n" +
+		"│ Sort    ▸ ││ Asc  │\n" +
+		"└───────────┘│ Desc │\n" +
+		"             └──────┘"
+	if got != want {
+		t.Fatalf("submenu layout:\n%s\nwant:\n%s", got, want)
+	}
+	m, cmd := menuKey(m, input.Enter)
+	if m.Open || cmd == nil {
+		t.Fatal("enter on a leaf should close and activate")
+	}
+	if msg := cmd().(MenuActivateMsg); msg.Menu != "ctx" || msg.ID != "desc" {
+		t.Fatalf("activation = %+v", msg)
+	}
+}
+
+func TestMenuAcceleratorClickAndDismiss(t *testing.T) {
+	m := testMenu().Show(0, 0)
+	_, cmd := m.Update(app.KeyMsg{Key: input.Key{Type: input.RuneKey, Rune: 'C'}})
+	if cmd == nil || cmd().(MenuActivateMsg).ID != "copy" {
+		t.Fatal("accelerator should activate copy")
+	}
+
+	_, cmd = m.Update(app.ClickMsg{Key: "ctx-paste"})
+	if cmd != nil {
+		t.Fatal("disabled item should not activate")
+	}
+
+	closed, _ := m.Update(app.ClickMsg{Key: ""})
+	if closed.Open {
+		t.Fatal("outside click should close")
+	}
+
+	m, _ = menuKey(m, input.Down)
+	m, _ = menuKey(m, input.Right)
+	m, _ = m.Update(app.DismissMsg{Scope: "ctx"})
+	if !m.Open || len(m.path) != 1 {
+		t.Fatal("escape should first close the submenu")
+	}
+	m, _ = m.Update(app.DismissMsg{Scope: "ctx"})
+	if m.Open {
+		t.Fatal("second escape should close the menu")
+	}
+}
+
+func TestMenuBar(t *testing.T) {
+	b := MenuBar{Key: "mb", Menus: []MenuBarMenu{
+		{Label: "File", Accel: 'f', Items: []MenuItem{{ID: "quit", Label: "Quit", Accel: 'q'}}},
+		{Label: "Edit", Accel: 'e', Items: []MenuItem{{ID: "undo", Label: "Undo"}}},
+	}}
+	got := tooeytest.RenderText(b.Render(""), 20, 1)
+	if got != " File  Edit" {
+		t.Fatalf("bar = %q", got)
+	}
+
+	b, _ = b.Update(app.KeyMsg{Key: input.Key{Type: input.RuneKey, Rune: 'e'}}, b.BarKey())
+	if !b.Open || b.Active != 1 || b.menu.X != 6 || b.menu.Y != 1 {
+		t.Fatalf("accelerator should open Edit below its title: open=%v active=%d at %d,%d", b.Open, b.Active, b.menu.X, b.menu.Y)
+	}
+	b, _ = b.Update(app.KeyMsg{Key: input.Key{Type: input.Right}}, "mb")
+	if b.Active != 0 || !b.Open {
+		t.Fatal("right should wrap to the File menu and keep it open")
+	}
+	b, cmd := b.Update(app.KeyMsg{Key: input.Key{Type: input.RuneKey, Rune: 'q'}}, "mb")
+	if b.Open || cmd == nil || cmd().(MenuActivateMsg) != (MenuActivateMsg{Menu: "mb", ID: "quit"}) {
+		t.Fatal("item accelerator should activate quit and close")
+	}
+
+	b, _ = b.Update(app.ClickMsg{Key: "mb-bar-1"}, "")
+	if !b.Open || b.Active != 1 {
+		t.Fatal("clicking a title should open its menu")
+	}
+}
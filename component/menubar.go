@@ -0,0 +1,163 @@
+package component
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/textwidth"
+)
+
+// MenuBarMenu is one top-level menu of a MenuBar.
+type MenuBarMenu struct {
+	Label string
+	Accel rune // underlined; opens the menu while the bar is focused
+	Items []MenuItem
+}
+
+// MenuBar is a horizontal bar of drop-down menus. The bar is one focus
+// stop keyed "<Key>-bar" and each title is keyed "<Key>-bar-<index>".
+// Left/Right move between titles, Enter/Down or a title's accelerator
+// opens its menu, and clicks open menus directly. While a menu is open,
+// Left/Right switch to the neighbouring menu.
+//
+// Render the bar where it belongs and Popup as the last layer of a
+// node.Overlay. Chosen items arrive as MenuActivateMsg with Menu set to
+// Key.
+type MenuBar struct {
+	Key   string
+	Menus []MenuBarMenu
+	// X, Y are the bar's screen position, used to drop menus below
+	// their titles.
+	X, Y int
+
+	Active int  // highlighted (or open) menu
+	Open   bool // whether Active's menu is showing
+
+	FG       node.Color
+	BG       node.Color // default 236
+	ActiveFG node.Color // default 0
+	ActiveBG node.Color // default 6
+
+	menu Menu
+}
+
+// BarKey returns the node key of the bar itself.
+func (b MenuBar) BarKey() string { return b.Key + "-bar" }
+
+// OpenMenu opens menu i below its title.
+func (b MenuBar) OpenMenu(i int) MenuBar {
+	if i < 0 || i >= len(b.Menus) {
+		return b
+	}
+	x := b.X
+	for _, m := range b.Menus[:i] {
+		x += textwidth.String(m.Label) + 2
+	}
+	b.Active, b.Open = i, true
+	b.menu = Menu{
+		Key:     b.Key,
+		Items:   b.Menus[i].Items,
+		FG:      b.FG,
+		BG:      b.BG,
+		HoverFG: b.ActiveFG,
+		HoverBG: b.ActiveBG,
+	}.Show(x, b.Y+1)
+	return b
+}
+
+// Close closes the open menu.
+func (b MenuBar) Close() MenuBar {
+	b.Open = false
+	b.menu = b.menu.Close()
+	return b
+}
+
+// Update handles keys while the bar (or its open menu) has focus, and
+// clicks on titles and menu items. The returned Cmd emits
+// MenuActivateMsg when an item is chosen.
+func (b MenuBar) Update(msg app.Msg, focused string) (MenuBar, app.Cmd) {
+	if len(b.Menus) == 0 {
+		return b, nil
+	}
+	if b.Open {
+		if km, ok := msg.(app.KeyMsg); ok && len(b.menu.path) <= 1 {
+			n := len(b.Menus)
+			switch km.Key.Type {
+			case input.Left:
+				return b.OpenMenu((b.Active + n - 1) % n), nil
+			case input.Right:
+				return b.OpenMenu((b.Active + 1) % n), nil
+			}
+		}
+		var cmd app.Cmd
+		b.menu, cmd = b.menu.Update(msg)
+		b.Open = b.menu.Open
+		return b, cmd
+	}
+
+	switch msg := msg.(type) {
+	case app.ClickMsg:
+		if rest, ok := strings.CutPrefix(msg.Key, b.BarKey()+"-"); ok {
+			if i, err := strconv.Atoi(rest); err == nil {
+				return b.OpenMenu(i), nil
+			}
+		}
+	case app.KeyMsg:
+		if focused != b.BarKey() {
+			break
+		}
+		n := len(b.Menus)
+		switch msg.Key.Type {
+		case input.Left:
+			b.Active = (b.Active + n - 1) % n
+		case input.Right:
+			b.Active = (b.Active + 1) % n
+		case input.Enter, input.Down:
+			return b.OpenMenu(b.Active), nil
+		case input.RuneKey:
+			for i, m := range b.Menus {
+				if m.Accel != 0 && unicode.ToLower(m.Accel) == unicode.ToLower(msg.Key.Rune) {
+					return b.OpenMenu(i), nil
+				}
+			}
+		}
+	}
+	return b, nil
+}
+
+// Render builds the bar. Pass the focused key so the active title is
+// highlighted only while the bar has focus or a menu is open.
+func (b MenuBar) Render(focused string) node.Node {
+	bg, activeFG, activeBG := b.BG, b.ActiveFG, b.ActiveBG
+	if bg == 0 {
+		bg = 236
+	}
+	if activeBG == 0 {
+		activeBG = 6
+	}
+	highlight := b.Open || focused == b.BarKey()
+	titles := make([]node.Node, 0, len(b.Menus)+1)
+	for i, m := range b.Menus {
+		fg, tbg := b.FG, bg
+		if highlight && i == b.Active {
+			fg, tbg = activeFG, activeBG
+		}
+		label := append(accelLabel(" ", m.Label, m.Accel, fg, tbg), node.TextStyled(" ", fg, tbg, 0))
+		titles = append(titles, node.Row(label...).WithKey(b.BarKey()+"-"+strconv.Itoa(i)).WithSize(textwidth.String(m.Label)+2, 1))
+	}
+	titles = append(titles, node.Bar("", b.FG, bg, 0))
+	return node.Row(titles...).WithKey(b.BarKey()).WithFocusable()
+}
+
+// Popup returns the open menu's overlay layer, or an empty
+// pass-through node.
+func (b MenuBar) Popup() node.Node {
+	if !b.Open {
+		return node.Column().WithPassThrough()
+	}
+	return b.menu.Render()
+}
@@ -0,0 +1,121 @@
+package component
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/node"
+)
+
+// RadioGroup is a single-choice control. The group is one focus stop
+// keyed with Key; each option is keyed "<Key>-<index>" so clicks pick
+// it directly, and the arrow keys move the choice while focused.
+type RadioGroup struct {
+	Key      string
+	Options  []string
+	Selected int
+	Disabled bool
+	// Horizontal lays the options out in a Row instead of a Column.
+	Horizontal bool
+
+	FG      node.Color
+	FocusFG node.Color // default 6
+}
+
+// Update moves the selection on a click on an option, or on the arrow
+// keys while the group is focused, and returns the updated group.
+func (r RadioGroup) Update(msg app.Msg, focused string) RadioGroup {
+	if r.Disabled || r.Key == "" {
+		return r
+	}
+	switch msg := msg.(type) {
+	case app.ClickMsg:
+		if i, ok := r.optionAt(msg.Key); ok {
+			r.Selected = i
+		}
+	case app.KeyMsg:
+		if focused != r.Key {
+			break
+		}
+		switch msg.Key.Type {
+		case input.Up, input.Left:
+			if r.Selected > 0 {
+				r.Selected--
+			}
+		case input.Down, input.Right:
+			if r.Selected < len(r.Options)-1 {
+				r.Selected++
+			}
+		case input.Home:
+			r.Selected = 0
+		case input.End:
+			r.Selected = len(r.Options) - 1
+		}
+	}
+	return r
+}
+
+// Value returns the selected option, or "" if none is selected.
+func (r RadioGroup) Value() string {
+	if r.Selected < 0 || r.Selected >= len(r.Options) {
+		return ""
+	}
+	return r.Options[r.Selected]
+}
+
+// optionAt parses an option key ("<Key>-<index>").
+func (r RadioGroup) optionAt(key string) (int, bool) {
+	rest, ok := strings.CutPrefix(key, r.Key+"-")
+	if !ok {
+		return 0, false
+	}
+	i, err := strconv.Atoi(rest)
+	if err != nil || i < 0 || i >= len(r.Options) {
+		return 0, false
+	}
+	return i, true
+}
+
+// Render builds the group as a Column (or Row when Horizontal) of
+// "(•) option" entries.
+func (r RadioGroup) Render(focused string) node.Node {
+	groupFocused := r.Key != "" && focused == r.Key && !r.Disabled
+	opts := make([]node.Node, 0, len(r.Options))
+	for i, opt := range r.Options {
+		text := "( ) " + opt
+		if i == r.Selected {
+			text = "(•) " + opt
+		}
+		if r.Horizontal {
+			text += "  "
+		}
+		var n node.Node
+		switch {
+		case r.Disabled:
+			n = node.TextStyled(text, 8, 0, node.Dim)
+		case i == r.Selected && groupFocused:
+			n = node.TextStyled(text, focusColor(r.FocusFG), 0, node.Bold)
+		case i == r.Selected:
+			n = node.TextStyled(text, r.FG, 0, node.Bold)
+		default:
+			n = node.TextStyled(text, r.FG, 0, 0)
+		}
+		if r.Key != "" {
+			n = n.WithKey(r.Key + "-" + strconv.Itoa(i))
+		}
+		opts = append(opts, n)
+	}
+	group := node.Column(opts...)
+	if r.Horizontal {
+		group = node.Row(opts...)
+	}
+	if r.Key != "" {
+		group = group.WithKey(r.Key)
+		if !r.Disabled {
+			group = group.WithFocusable()
+		}
+	}
+	return group
+}
@@ -41,6 +41,8 @@ func Spinner(label string, frameIdx int, style SpinnerStyle, fg node.Color) node
 type SpinnerTickMsg struct{}
 
 // SpinnerTick returns a Cmd that sends a SpinnerTickMsg after the given interval.
+// While other animations are requesting frames anyway, animate.Cycle can
+// pick the frame index from app.FrameMsg's time instead.
 func SpinnerTick(interval time.Duration) app.Cmd {
 	return func() app.Msg {
 		time.Sleep(interval)
@@ -0,0 +1,151 @@
+package component
+
+import (
+	"math"
+
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/node"
+)
+
+// Split lays out two panes side by side (or stacked when Vertical) with
+// a one-cell divider between them. The divider is keyed with Key and
+// focusable: drag it with the mouse, or focus it and use the arrow keys
+// (Home/End jump to the limits).
+//
+// Ratio is the first pane's share of the space left after the divider.
+// Update needs Size, the split's total extent along its axis (its width,
+// or height when Vertical), to turn drags into ratio changes; set it
+// from your layout or ResizeMsg.
+type Split struct {
+	Key      string
+	Vertical bool
+	Ratio    float64 // default 0.5
+	Size     int
+
+	// MinFirst and MinSecond are the smallest pane sizes in cells.
+	MinFirst  int
+	MinSecond int
+
+	DividerFG node.Color // default 8
+	FocusFG   node.Color // default 6
+}
+
+// Update moves the divider on a DragMsg that started on it, or on the
+// arrow keys while focused is the split's Key.
+func (s Split) Update(msg app.Msg, focused string) Split {
+	if s.Key == "" {
+		return s
+	}
+	switch msg := msg.(type) {
+	case app.DragMsg:
+		if msg.Key != s.Key {
+			break
+		}
+		d := msg.DX
+		if s.Vertical {
+			d = msg.DY
+		}
+		return s.setPos(s.pos() + d)
+	case app.KeyMsg:
+		if focused != s.Key {
+			break
+		}
+		back, fwd := input.Left, input.Right
+		if s.Vertical {
+			back, fwd = input.Up, input.Down
+		}
+		switch msg.Key.Type {
+		case back:
+			return s.setPos(s.pos() - 1)
+		case fwd:
+			return s.setPos(s.pos() + 1)
+		case input.Home:
+			return s.setPos(0)
+		case input.End:
+			return s.setPos(s.Size)
+		}
+	}
+	return s
+}
+
+func (s Split) ratio() float64 {
+	if s.Ratio <= 0 || s.Ratio > 1 {
+		return 0.5
+	}
+	return s.Ratio
+}
+
+// avail is the space shared by the two panes.
+func (s Split) avail() int {
+	return max(s.Size-1, 0)
+}
+
+// pos returns the first pane's size in cells.
+func (s Split) pos() int {
+	return s.clampPos(int(math.Round(s.ratio() * float64(s.avail()))))
+}
+
+func (s Split) clampPos(p int) int {
+	hi := s.avail() - s.MinSecond
+	p = min(p, hi)
+	return max(p, min(s.MinFirst, hi), 0)
+}
+
+// setPos sets the first pane's size in cells, within the min sizes.
+func (s Split) setPos(p int) Split {
+	if s.avail() == 0 {
+		return s
+	}
+	s.Ratio = float64(s.clampPos(p)) / float64(s.avail())
+	return s
+}
+
+// Render builds the split as a Row (or Column when Vertical) of the
+// two panes with flex weights from the ratio and the divider between.
+func (s Split) Render(first, second node.Node, focused string) node.Node {
+	// Weight in cells when the size is known so the split is exact;
+	// otherwise in thousandths of the ratio.
+	w1, total := s.pos(), s.avail()
+	if s.Size <= 0 {
+		total = 1000
+		w1 = int(math.Round(s.ratio() * 1000))
+	}
+	w2 := total - w1
+
+	fg := s.DividerFG
+	if fg == 0 {
+		fg = 8
+	}
+	if s.Key != "" && focused == s.Key {
+		fg = focusColor(s.FocusFG)
+	}
+	divider := s.divider(fg)
+	if s.Key != "" {
+		divider = divider.WithKey(s.Key).WithFocusable()
+	}
+
+	a, b := pane(first, w1), pane(second, w2)
+	if s.Vertical {
+		return node.Column(a, divider, b)
+	}
+	return node.Row(a, divider, b)
+}
+
+// divider is a one-cell line across the split, drawn at whatever
+// length layout gives it so it never adds to the split's measured size.
+func (s Split) divider(fg node.Color) node.Node {
+	if s.Vertical {
+		return node.HRule().WithFG(fg)
+	}
+	return node.VRule().WithFG(fg)
+}
+
+// pane wraps a split child with a flex weight; a zero weight collapses
+// the pane instead of falling back to its intrinsic size.
+func pane(child node.Node, weight int) node.Node {
+	if weight <= 0 {
+		return node.Row()
+	}
+	return node.Column(child).WithFlex(weight)
+}
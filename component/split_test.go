@@ -0,0 +1,81 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/cell"
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/tooeytest"
+)
+
+func TestSplitRender(t *testing.T) {
+	s := Split{Key: "sp", Ratio: 0.5, Size: 9}
+	tooeytest.AssertFrame(t, s.Render(node.Text("left"), node.Text("right"), ""), 9, 2, `
+		left│righ
+		    │`)
+
+	v := Split{Key: "sp", Vertical: true, Ratio: 0.5, Size: 5}
+	tooeytest.AssertFrame(t, v.Render(node.Text("top"), node.Text("bottom"), ""), 6, 5, `
+		top
+
+		──────
+		bottom`)
+}
+
+func TestSplitASCIIDivider(t *testing.T) {
+	cell.SetASCIIBorders(true)
+	defer cell.SetASCIIBorders(false)
+
+	s := Split{Ratio: 0.5, Size: 5}
+	tooeytest.AssertFrame(t, s.Render(node.Text("a"), node.Text("b"), ""), 5, 2, `
+		a |b
+		  |`)
+	v := Split{Vertical: true, Ratio: 0.5, Size: 3}
+	tooeytest.AssertFrame(t, v.Render(node.Text("a"), node.Text("b"), ""), 3, 3, `
+		a
+		---
+		b`)
+}
+
+func TestSplitSizesFromLayout(t *testing.T) {
+	// The divider spans the split as laid out, without inflating its
+	// measured size: a footer below the split stays on screen.
+	s := Split{Key: "sp", Ratio: 0.5, Size: 9}
+	view := node.Column(s.Render(node.Text("left"), node.Text("right"), ""), node.Text("footer"))
+	tooeytest.AssertFrame(t, view, 9, 3, `
+		left│righ
+		footer`)
+
+	v := Split{Key: "sp", Vertical: true, Ratio: 0.5, Size: 3}
+	view = node.Row(v.Render(node.Text("a"), node.Text("b"), "").WithSize(4, 0), node.Text("|side"))
+	tooeytest.AssertFrame(t, view, 10, 3, `
+		a   |side
+		────
+		b`)
+}
+
+func TestSplitDragAndKeys(t *testing.T) {
+	s := Split{Key: "sp", Ratio: 0.5, Size: 21, MinFirst: 3, MinSecond: 4}
+	s = s.Update(app.DragMsg{Key: "sp", DX: 5}, "")
+	if got := s.pos(); got != 15 {
+		t.Fatalf("pos after drag = %d, want 15", got)
+	}
+	s = s.Update(app.DragMsg{Key: "sp", DX: 10}, "")
+	if got := s.pos(); got != 16 {
+		t.Fatalf("drag should stop at MinSecond, pos = %d", got)
+	}
+	s = s.Update(app.DragMsg{Key: "other", DX: -10}, "")
+	if got := s.pos(); got != 16 {
+		t.Fatal("drags that started elsewhere should be ignored")
+	}
+	s = s.Update(app.KeyMsg{Key: input.Key{Type: input.Left}}, "sp")
+	if got := s.pos(); got != 15 {
+		t.Fatalf("left should nudge, pos = %d", got)
+	}
+	s = s.Update(app.KeyMsg{Key: input.Key{Type: input.Home}}, "sp")
+	if got := s.pos(); got != 3 {
+		t.Fatalf("home should stop at MinFirst, pos = %d", got)
+	}
+}
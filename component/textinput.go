@@ -10,13 +10,49 @@ import (
 )
 
 // TextInput holds state for a multi-line text input with cursor.
+//
+// Beyond plain editing it keeps an undo history (Ctrl+Z undo, Ctrl+R
+// redo; consecutive typing coalesces into one step per word), a
+// selection extended with Shift+arrows and replaced by typing, and
+// readline-style kills (Ctrl+W / Alt+Backspace word, Ctrl+U to line
+// start, Ctrl+K to line end) that Ctrl+Y yanks back.
 type TextInput struct {
 	Value       string
 	Cursor      int // rune offset into Value
 	Placeholder string
 	Focused     bool
+
+	// Anchor is the fixed end of the selection while Selecting; the
+	// cursor is the moving end.
+	Anchor    int
+	Selecting bool
+
+	undo, redo []textSnapshot
+	lastEdit   editKind
+	killBuf    string
+	lastKill   bool
 }
 
+// textSnapshot is one undo/redo state.
+type textSnapshot struct {
+	value  string
+	cursor int
+}
+
+// editKind classifies edits so runs of the same kind coalesce into a
+// single undo step.
+type editKind int
+
+const (
+	editNone editKind = iota
+	editInsert
+	editDelete
+	editOther
+)
+
+// maxUndo bounds the undo history.
+const maxUndo = 100
+
 // NewTextInput creates a text input with a placeholder.
 func NewTextInput(placeholder string) TextInput {
 	return TextInput{Placeholder: placeholder, Focused: true}
@@ -25,59 +61,145 @@ func NewTextInput(placeholder string) TextInput {
 // Update handles a key event and returns the updated TextInput.
 func (ti TextInput) Update(key input.Key) TextInput {
 	runes := []rune(ti.Value)
+	ti.Cursor = clampInt(ti.Cursor, 0, len(runes))
+	kill := false
 	switch key.Type {
 	case input.RuneKey:
-		runes = append(runes[:ti.Cursor], append([]rune{key.Rune}, runes[ti.Cursor:]...)...)
-		ti.Cursor++
+		ti = ti.replaceSelection(string(key.Rune), editInsert)
 	case input.ShiftEnter:
-		runes = append(runes[:ti.Cursor], append([]rune{'\n'}, runes[ti.Cursor:]...)...)
-		ti.Cursor++
+		ti = ti.replaceSelection("\n", editOther)
 	case input.Backspace:
-		if ti.Cursor > 0 {
-			runes = append(runes[:ti.Cursor-1], runes[ti.Cursor:]...)
-			ti.Cursor--
+		if ti.Selecting {
+			ti = ti.replaceSelection("", editOther)
+		} else if ti.Cursor > 0 {
+			ti = ti.edit(ti.Cursor-1, ti.Cursor, "", editDelete)
 		}
 	case input.Delete:
-		if ti.Cursor < len(runes) {
-			runes = append(runes[:ti.Cursor], runes[ti.Cursor+1:]...)
+		if ti.Selecting {
+			ti = ti.replaceSelection("", editOther)
+		} else if ti.Cursor < len(runes) {
+			ti = ti.edit(ti.Cursor, ti.Cursor+1, "", editDelete)
+		}
+	case input.CtrlW, input.AltBackspace:
+		if ti.Selecting {
+			start, end, _ := ti.Selection()
+			ti = ti.kill(start, end, false)
+		} else {
+			ti = ti.kill(wordLeft(runes, ti.Cursor), ti.Cursor, true)
+		}
+		kill = true
+	case input.CtrlU:
+		ti = ti.kill(lineStart(runes, ti.Cursor), ti.Cursor, true)
+		kill = true
+	case input.CtrlK:
+		end := lineEnd(runes, ti.Cursor)
+		if end == ti.Cursor && end < len(runes) {
+			end++ // at end of line: kill the newline, joining lines
+		}
+		ti = ti.kill(ti.Cursor, end, false)
+		kill = true
+	case input.CtrlY:
+		if ti.killBuf != "" {
+			ti = ti.replaceSelection(ti.killBuf, editOther)
 		}
+	case input.CtrlZ:
+		ti = ti.Undo()
+	case input.CtrlR:
+		ti = ti.Redo()
 	case input.Left:
-		if ti.Cursor > 0 {
-			ti.Cursor--
+		if start, _, ok := ti.Selection(); ok {
+			ti = ti.moveTo(start, false)
+		} else {
+			ti = ti.moveTo(ti.Cursor-1, false)
 		}
 	case input.Right:
-		if ti.Cursor < len(runes) {
-			ti.Cursor++
+		if _, end, ok := ti.Selection(); ok {
+			ti = ti.moveTo(end, false)
+		} else {
+			ti = ti.moveTo(ti.Cursor+1, false)
 		}
 	case input.Home:
 		// Move to start of current line
-		ti.Cursor = lineStart(runes, ti.Cursor)
+		ti = ti.moveTo(lineStart(runes, ti.Cursor), false)
 	case input.End:
 		// Move to end of current line
-		ti.Cursor = lineEnd(runes, ti.Cursor)
+		ti = ti.moveTo(lineEnd(runes, ti.Cursor), false)
 	case input.Up:
-		ti.Cursor = moveCursorUp(runes, ti.Cursor)
+		ti = ti.moveTo(moveCursorUp(runes, ti.Cursor), false)
 	case input.Down:
-		ti.Cursor = moveCursorDown(runes, ti.Cursor)
+		ti = ti.moveTo(moveCursorDown(runes, ti.Cursor), false)
 	case input.AltLeft:
-		ti.Cursor = wordLeft(runes, ti.Cursor)
+		ti = ti.moveTo(wordLeft(runes, ti.Cursor), false)
 	case input.AltRight:
-		ti.Cursor = wordRight(runes, ti.Cursor)
+		ti = ti.moveTo(wordRight(runes, ti.Cursor), false)
+	case input.ShiftLeft:
+		ti = ti.moveTo(ti.Cursor-1, true)
+	case input.ShiftRight:
+		ti = ti.moveTo(ti.Cursor+1, true)
+	case input.ShiftHome:
+		ti = ti.moveTo(lineStart(runes, ti.Cursor), true)
+	case input.ShiftEnd:
+		ti = ti.moveTo(lineEnd(runes, ti.Cursor), true)
+	case input.ShiftUp:
+		ti = ti.moveTo(moveCursorUp(runes, ti.Cursor), true)
+	case input.ShiftDown:
+		ti = ti.moveTo(moveCursorDown(runes, ti.Cursor), true)
 	}
-	ti.Value = string(runes)
+	ti.lastKill = kill
 	return ti
 }
 
-// Paste inserts text at the cursor position in a single operation.
+// Paste inserts text at the cursor position in a single operation,
+// replacing the selection if there is one.
 func (ti TextInput) Paste(text string) TextInput {
-	runes := []rune(ti.Value)
-	pasteRunes := []rune(text)
-	newRunes := make([]rune, 0, len(runes)+len(pasteRunes))
-	newRunes = append(newRunes, runes[:ti.Cursor]...)
-	newRunes = append(newRunes, pasteRunes...)
-	newRunes = append(newRunes, runes[ti.Cursor:]...)
-	ti.Value = string(newRunes)
-	ti.Cursor += len(pasteRunes)
+	ti.Cursor = clampInt(ti.Cursor, 0, len([]rune(ti.Value)))
+	ti = ti.replaceSelection(text, editOther)
+	ti.lastKill = false
+	return ti
+}
+
+// Selection returns the selected rune range [start, end), with ok false
+// when nothing is selected.
+func (ti TextInput) Selection() (start, end int, ok bool) {
+	if !ti.Selecting || ti.Anchor == ti.Cursor {
+		return 0, 0, false
+	}
+	n := len([]rune(ti.Value))
+	start, end = clampInt(ti.Anchor, 0, n), clampInt(ti.Cursor, 0, n)
+	if start > end {
+		start, end = end, start
+	}
+	return start, end, true
+}
+
+// SelectedText returns the selected text, or "" if nothing is selected.
+func (ti TextInput) SelectedText() string {
+	start, end, ok := ti.Selection()
+	if !ok {
+		return ""
+	}
+	return string([]rune(ti.Value)[start:end])
+}
+
+// Undo reverts the last edit step.
+func (ti TextInput) Undo() TextInput {
+	if len(ti.undo) == 0 {
+		return ti
+	}
+	ti.redo = append(ti.redo, textSnapshot{ti.Value, ti.Cursor})
+	ti = ti.restore(ti.undo[len(ti.undo)-1])
+	ti.undo = ti.undo[:len(ti.undo)-1]
+	return ti
+}
+
+// Redo re-applies the last undone edit step.
+func (ti TextInput) Redo() TextInput {
+	if len(ti.redo) == 0 {
+		return ti
+	}
+	ti.undo = append(ti.undo, textSnapshot{ti.Value, ti.Cursor})
+	ti = ti.restore(ti.redo[len(ti.redo)-1])
+	ti.redo = ti.redo[:len(ti.redo)-1]
 	return ti
 }
 
@@ -86,9 +208,86 @@ func (ti TextInput) Submit() (string, TextInput) {
 	val := strings.TrimSpace(ti.Value)
 	ti.Value = ""
 	ti.Cursor = 0
+	ti.Selecting = false
+	ti.undo, ti.redo = nil, nil
+	ti.lastEdit = editNone
 	return val, ti
 }
 
+func (ti TextInput) restore(snap textSnapshot) TextInput {
+	ti.Value = snap.value
+	ti.Cursor = snap.cursor
+	ti.Selecting = false
+	ti.lastEdit = editNone
+	return ti
+}
+
+// moveTo places the cursor at pos. With extend, the selection grows
+// from the current cursor; without it, any selection is dropped.
+func (ti TextInput) moveTo(pos int, extend bool) TextInput {
+	if extend && !ti.Selecting {
+		ti.Anchor = ti.Cursor
+		ti.Selecting = true
+	} else if !extend {
+		ti.Selecting = false
+	}
+	ti.Cursor = clampInt(pos, 0, len([]rune(ti.Value)))
+	ti.lastEdit = editNone
+	return ti
+}
+
+// replaceSelection inserts text at the cursor, replacing the selection.
+func (ti TextInput) replaceSelection(text string, kind editKind) TextInput {
+	if start, end, ok := ti.Selection(); ok {
+		return ti.edit(start, end, text, editOther)
+	}
+	return ti.edit(ti.Cursor, ti.Cursor, text, kind)
+}
+
+// kill removes runes [start, end) into the kill buffer. Consecutive
+// kills accumulate, prepending for backward kills.
+func (ti TextInput) kill(start, end int, backward bool) TextInput {
+	if start >= end {
+		return ti
+	}
+	text := string([]rune(ti.Value)[start:end])
+	switch {
+	case !ti.lastKill:
+		ti.killBuf = text
+	case backward:
+		ti.killBuf = text + ti.killBuf
+	default:
+		ti.killBuf += text
+	}
+	return ti.edit(start, end, "", editOther)
+}
+
+// edit replaces runes [start, end) with text, recording an undo step
+// unless it continues a run of the same kind of edit.
+func (ti TextInput) edit(start, end int, text string, kind editKind) TextInput {
+	// Typing coalesces per word: a space starts a new undo step.
+	coalesce := kind != editOther && kind == ti.lastEdit && !(kind == editInsert && text == " ")
+	if !coalesce {
+		ti.undo = append(ti.undo, textSnapshot{ti.Value, ti.Cursor})
+		if len(ti.undo) > maxUndo {
+			ti.undo = ti.undo[len(ti.undo)-maxUndo:]
+		}
+	}
+	ti.redo = nil
+
+	runes := []rune(ti.Value)
+	ins := []rune(text)
+	out := make([]rune, 0, len(runes)-(end-start)+len(ins))
+	out = append(out, runes[:start]...)
+	out = append(out, ins...)
+	out = append(out, runes[end:]...)
+	ti.Value = string(out)
+	ti.Cursor = start + len(ins)
+	ti.Selecting = false
+	ti.lastEdit = kind
+	return ti
+}
+
 // LineCount returns the number of display lines.
 func (ti TextInput) LineCount() int {
 	if ti.Value == "" {
@@ -131,11 +330,17 @@ func (ti TextInput) Render(prefix string, fg, bg node.Color, width int) node.Nod
 			lp = prefixWidth // continuation prefix same width
 		}
 		wrapped := wrapLine(line, width, lp)
+		lineRunes := []rune(line)
+		pos := 0
 		for _, wl := range wrapped {
-			displayLines = append(displayLines, displayLine{text: wl, runeStart: runeOffset})
-			runeOffset += len([]rune(wl))
+			displayLines = append(displayLines, displayLine{text: wl, runeStart: runeOffset + pos})
+			pos += len([]rune(wl))
+			// wrapLine drops the space it breaks at.
+			if pos < len(lineRunes) && lineRunes[pos] == ' ' {
+				pos++
+			}
 		}
-		runeOffset++ // account for the \n between logical lines
+		runeOffset += len(lineRunes) + 1 // account for the \n between logical lines
 	}
 
 	// Find which display line the cursor is on
@@ -156,34 +361,18 @@ func (ti TextInput) Render(prefix string, fg, bg node.Color, width int) node.Nod
 		}
 	}
 
+	selStart, selEnd, _ := ti.Selection()
 	var lineNodes []node.Node
 	for i, dl := range displayLines {
-		var ln node.Node
 		linePrefix := contPrefix
 		if i == 0 {
 			linePrefix = prefix
 		}
-
+		col := -1
 		if i == cursorDisplayLine && ti.Focused {
-			lineRunes := []rune(dl.text)
-			before := string(lineRunes[:cursorCol])
-			var cursorChar string
-			var after string
-			if cursorCol < len(lineRunes) {
-				cursorChar = string(lineRunes[cursorCol])
-				after = string(lineRunes[cursorCol+1:])
-			} else {
-				cursorChar = " "
-			}
-			ln = node.Row(
-				node.TextStyled(linePrefix+before, fg, bg, 0),
-				node.TextStyled(cursorChar, node.Color(0), node.Color(15), 0),
-				node.TextStyled(after, fg, bg, 0),
-			)
-		} else {
-			ln = node.TextStyled(linePrefix+dl.text, fg, bg, 0)
+			col = cursorCol
 		}
-		lineNodes = append(lineNodes, ln)
+		lineNodes = append(lineNodes, renderInputLine(linePrefix, []rune(dl.text), dl.runeStart, col, selStart, selEnd, fg, bg))
 	}
 
 	if len(lineNodes) == 1 {
@@ -192,6 +381,67 @@ func (ti TextInput) Render(prefix string, fg, bg node.Color, width int) node.Nod
 	return node.Column(lineNodes...)
 }
 
+// renderInputLine builds one display line: the prefix and text, with a
+// block cursor at cursorCol (-1 for none) and runes in the selection
+// [selStart, selEnd) shown reversed. runeStart is the line's offset in
+// the full value.
+func renderInputLine(prefix string, runes []rune, runeStart, cursorCol, selStart, selEnd int, fg, bg node.Color) node.Node {
+	const (
+		plain = iota
+		selected
+		cursor
+	)
+	state := func(i int) int {
+		switch {
+		case i == cursorCol:
+			return cursor
+		case runeStart+i >= selStart && runeStart+i < selEnd:
+			return selected
+		}
+		return plain
+	}
+
+	lineEnd := runeStart + len(runes)
+	if cursorCol < 0 && (selEnd <= runeStart || selStart >= lineEnd) {
+		return node.TextStyled(prefix+string(runes), fg, bg, 0)
+	}
+
+	spans := []node.Node{}
+	text := prefix
+	for i := 0; i < len(runes); {
+		st := state(i)
+		j := i + 1
+		for st != cursor && j < len(runes) && state(j) == st {
+			j++
+		}
+		seg := string(runes[i:j])
+		switch st {
+		case plain:
+			text += seg
+		case selected:
+			if text != "" {
+				spans = append(spans, node.TextStyled(text, fg, bg, 0))
+				text = ""
+			}
+			spans = append(spans, node.TextStyled(seg, fg, bg, node.Reverse))
+		case cursor:
+			spans = append(spans, node.TextStyled(text, fg, bg, 0))
+			text = ""
+			spans = append(spans, node.TextStyled(seg, node.Color(0), node.Color(15), 0))
+		}
+		i = j
+	}
+	if cursorCol >= len(runes) {
+		spans = append(spans, node.TextStyled(text, fg, bg, 0))
+		text = ""
+		spans = append(spans, node.TextStyled(" ", node.Color(0), node.Color(15), 0))
+	}
+	if text != "" {
+		spans = append(spans, node.TextStyled(text, fg, bg, 0))
+	}
+	return node.Row(spans...)
+}
+
 // splitLines splits on newline, always returning at least one element.
 func splitLines(s string) []string {
 	if s == "" {
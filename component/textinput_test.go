@@ -0,0 +1,128 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/tooeytest"
+)
+
+func typeText(ti TextInput, s string) TextInput {
+	for _, r := range s {
+		ti = ti.Update(input.Key{Type: input.RuneKey, Rune: r})
+	}
+	return ti
+}
+
+func key(t input.KeyType) input.Key { return input.Key{Type: t} }
+
+func TestTextInputUndoCoalescesWords(t *testing.T) {
+	ti := typeText(NewTextInput(""), "hello world")
+	ti = ti.Update(key(input.CtrlZ))
+	if ti.Value != "hello" {
+		t.Fatalf("undo should remove the last word, got %q", ti.Value)
+	}
+	ti = ti.Update(key(input.CtrlZ))
+	if ti.Value != "" {
+		t.Fatalf("second undo should remove the first word, got %q", ti.Value)
+	}
+	ti = ti.Update(key(input.CtrlR))
+	ti = ti.Update(key(input.CtrlR))
+	if ti.Value != "hello world" || ti.Cursor != 11 {
+		t.Fatalf("redo should restore the text, got %q@%d", ti.Value, ti.Cursor)
+	}
+	ti = ti.Update(key(input.CtrlZ))
+	ti = typeText(ti, "!")
+	if ti = ti.Update(key(input.CtrlR)); ti.Value != "hello!" {
+		t.Fatalf("a new edit should clear redo, got %q", ti.Value)
+	}
+}
+
+func TestTextInputSelectionReplace(t *testing.T) {
+	ti := typeText(NewTextInput(""), "abcdef")
+	ti = ti.Update(key(input.ShiftLeft))
+	ti = ti.Update(key(input.ShiftLeft))
+	if got := ti.SelectedText(); got != "ef" {
+		t.Fatalf("shift+left should extend the selection, got %q", got)
+	}
+	ti = typeText(ti, "X")
+	if ti.Value != "abcdX" || ti.Selecting {
+		t.Fatalf("typing should replace the selection, got %q", ti.Value)
+	}
+	ti = ti.Update(key(input.ShiftHome))
+	ti = ti.Update(key(input.Backspace))
+	if ti.Value != "" {
+		t.Fatalf("backspace should delete the selection, got %q", ti.Value)
+	}
+	if ti = ti.Update(key(input.CtrlZ)); ti.Value != "abcdX" {
+		t.Fatalf("deleting a selection should be undoable, got %q", ti.Value)
+	}
+}
+
+func TestTextInputKillYank(t *testing.T) {
+	ti := typeText(NewTextInput(""), "one two three")
+	ti = ti.Update(key(input.CtrlW))
+	ti = ti.Update(key(input.AltBackspace))
+	if ti.Value != "one " {
+		t.Fatalf("word kills should delete backward words, got %q", ti.Value)
+	}
+	ti = ti.Update(key(input.Home))
+	ti = ti.Update(key(input.CtrlK))
+	if ti.Value != "" {
+		t.Fatalf("ctrl+k should kill to end of line, got %q", ti.Value)
+	}
+	ti = ti.Update(key(input.CtrlY))
+	if ti.Value != "one " {
+		t.Fatalf("yank should insert the last kill, got %q", ti.Value)
+	}
+
+	ti = typeText(NewTextInput(""), "a b")
+	ti = ti.Update(key(input.CtrlW))
+	ti = ti.Update(key(input.CtrlW))
+	ti = ti.Update(key(input.CtrlY))
+	if ti.Value != "a b" {
+		t.Fatalf("consecutive kills should accumulate, got %q", ti.Value)
+	}
+}
+
+func TestTextInputCtrlUKillsToLineStart(t *testing.T) {
+	ti := typeText(NewTextInput(""), "first")
+	ti = ti.Update(key(input.ShiftEnter))
+	ti = typeText(ti, "second")
+	ti = ti.Update(key(input.Left))
+	ti = ti.Update(key(input.CtrlU))
+	if ti.Value != "first\nd" || ti.Cursor != 6 {
+		t.Fatalf("ctrl+u should stop at the line start, got %q@%d", ti.Value, ti.Cursor)
+	}
+}
+
+func TestTextInputRenderSelection(t *testing.T) {
+	ti := typeText(NewTextInput(""), "abcd")
+	ti = ti.Update(key(input.ShiftLeft))
+	ti = ti.Update(key(input.ShiftLeft))
+	n := ti.Render("> ", 0, 0, 0)
+	buf := tooeytest.Render(n, 10, 1)
+	if got := tooeytest.BufferText(buf); got != "> abcd" {
+		t.Fatalf("unexpected render: %q", got)
+	}
+	// Cursor on "c" (the moving end), "d" selected, "b" untouched.
+	if buf.Get(4, 0).BG != 15 {
+		t.Fatal("cursor should be drawn at the moving end of the selection")
+	}
+	if buf.Get(5, 0).Style&node.Reverse == 0 || buf.Get(3, 0).Style&node.Reverse != 0 {
+		t.Fatal("only the selected rune should be reversed")
+	}
+}
+
+func TestTextInputWrappedCursor(t *testing.T) {
+	ti := typeText(NewTextInput(""), "aaa bbb")
+	got := tooeytest.RenderText(ti.Render("", 0, 0, 5), 10, 2)
+	if got != "aaa\nbbb" {
+		t.Fatalf("unexpected wrap: %q", got)
+	}
+	n := ti.Render("", 0, 0, 5)
+	if n.Children[1].Children[1].Props.BG != 15 {
+		t.Fatal("cursor should sit after the last rune of the wrapped line")
+	}
+}
@@ -0,0 +1,129 @@
+package component
+
+import (
+	"time"
+
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/node"
+)
+
+// DefaultToastDuration is how long a pushed toast stays when
+// Toasts.Duration is unset.
+const DefaultToastDuration = 4 * time.Second
+
+// Toast is one notification in a Toasts stack.
+type Toast struct {
+	ID    int
+	Text  string
+	Style BadgeStyle
+}
+
+// ToastExpiredMsg is sent by the Cmd returned from Toasts.Push when a
+// toast's time is up. Pass it to Toasts.Update.
+type ToastExpiredMsg struct {
+	ID int
+}
+
+// Toasts is a stack of transient notifications shown in the bottom-right
+// corner. Push returns a Cmd that expires the toast after Duration;
+// forward ToastExpiredMsg to Update to remove it.
+//
+// The stack renders as an overlay layer marked PassThrough, so it never
+// takes focus or swallows clicks meant for the UI beneath it.
+type Toasts struct {
+	Items []Toast
+
+	// Duration is how long a toast is shown (default
+	// DefaultToastDuration).
+	Duration time.Duration
+	// Max caps how many toasts are shown at once; older ones are
+	// dropped when it is exceeded (default 5).
+	Max int
+
+	BG node.Color // default 236
+
+	nextID int
+}
+
+// Push adds a toast and returns the updated stack with a Cmd that
+// expires it after Duration.
+func (t Toasts) Push(text string, style BadgeStyle) (Toasts, app.Cmd) {
+	d := t.Duration
+	if d <= 0 {
+		d = DefaultToastDuration
+	}
+	return t.PushFor(text, style, d)
+}
+
+// PushFor adds a toast shown for d. A d <= 0 keeps the toast until it
+// is dismissed, and returns a nil Cmd.
+func (t Toasts) PushFor(text string, style BadgeStyle, d time.Duration) (Toasts, app.Cmd) {
+	t.nextID++
+	id := t.nextID
+	limit := t.Max
+	if limit <= 0 {
+		limit = 5
+	}
+	items := append([]Toast(nil), t.Items...)
+	items = append(items, Toast{ID: id, Text: text, Style: style})
+	if len(items) > limit {
+		items = items[len(items)-limit:]
+	}
+	t.Items = items
+	if d <= 0 {
+		return t, nil
+	}
+	return t, func() app.Msg {
+		time.Sleep(d)
+		return ToastExpiredMsg{ID: id}
+	}
+}
+
+// Dismiss removes the toast with the given ID.
+func (t Toasts) Dismiss(id int) Toasts {
+	items := make([]Toast, 0, len(t.Items))
+	for _, it := range t.Items {
+		if it.ID != id {
+			items = append(items, it)
+		}
+	}
+	t.Items = items
+	return t
+}
+
+// Update removes expired toasts.
+func (t Toasts) Update(msg app.Msg) Toasts {
+	if m, ok := msg.(ToastExpiredMsg); ok {
+		return t.Dismiss(m.ID)
+	}
+	return t
+}
+
+// Render builds the overlay layer: toasts stacked in the bottom-right
+// corner, newest at the bottom. Place it last in a node.Overlay, or use
+// Over.
+func (t Toasts) Render() node.Node {
+	bg := t.BG
+	if bg == 0 {
+		bg = 236
+	}
+	rows := make([]node.Node, 0, len(t.Items)+1)
+	rows = append(rows, node.Spacer())
+	for _, it := range t.Items {
+		cfg := badgeConfig[it.Style]
+		box := node.Box(node.BorderRounded, node.Row(
+			node.TextStyled(cfg.icon+" ", cfg.fg, bg, node.Bold),
+			node.TextStyled(it.Text, 0, bg, 0),
+		)).WithFG(cfg.fg).WithBG(bg).WithPadding(0, 1, 0, 1)
+		rows = append(rows, node.Row(node.Spacer(), box))
+	}
+	return node.Column(rows...).WithPassThrough()
+}
+
+// Over layers the toasts on top of base.
+func (t Toasts) Over(base node.Node) node.Node {
+	if len(t.Items) == 0 {
+		return base
+	}
+	return node.Overlay(base, t.Render())
+}
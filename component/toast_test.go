@@ -0,0 +1,51 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/stukennedy/tooey/layout"
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/tooeytest"
+)
+
+func TestToastsRenderBottomRight(t *testing.T) {
+	var ts Toasts
+	ts, _ = ts.Push("Saved", BadgeSuccess)
+	tooeytest.AssertFrame(t, ts.Over(node.Text("base")), 16, 4, `
+		base
+		     ╭─────────╮
+		     │ ✓ Saved │
+		     ╰─────────╯`)
+}
+
+func TestToastsExpireAndCap(t *testing.T) {
+	ts := Toasts{Max: 2}
+	ts, expire := ts.PushFor("one", BadgeInfo, 0)
+	if expire != nil {
+		t.Fatal("sticky toast should not schedule expiry")
+	}
+	ts, _ = ts.Push("two", BadgeWarning)
+	ts, _ = ts.Push("three", BadgeError)
+	if len(ts.Items) != 2 || ts.Items[0].Text != "two" {
+		t.Fatalf("items = %+v, want the newest two", ts.Items)
+	}
+	ts = ts.Update(ToastExpiredMsg{ID: ts.Items[0].ID})
+	if len(ts.Items) != 1 || ts.Items[0].Text != "three" {
+		t.Fatalf("expiry should remove the toast, got %+v", ts.Items)
+	}
+}
+
+func TestToastsPassClicksThrough(t *testing.T) {
+	var ts Toasts
+	ts, _ = ts.Push("Connection lost", BadgeError)
+	base := node.Column(
+		node.Spacer(),
+		node.Text("button").WithKey("btn").WithFocusable(),
+	)
+	lt := layout.Layout(ts.Over(base), 30, 5)
+	// The button row sits under the toast layer (and the toast box).
+	path := layout.HitTest(lt, 0, 4)
+	if got := path[len(path)-1].Node.Props.Key; got != "btn" {
+		t.Fatalf("click under toast layer hit %q, want btn", got)
+	}
+}
@@ -0,0 +1,328 @@
+package component
+
+import (
+	"strings"
+
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/node"
+)
+
+// TreeNode is one entry in a Tree. IDs must be unique within the tree.
+type TreeNode struct {
+	ID       string
+	Label    string
+	Children []TreeNode
+	// HasChildren marks a node whose children are loaded lazily: it
+	// shows an expander while Children is still empty.
+	HasChildren bool
+	Expanded    bool
+	// Loading is set while a lazy load is in flight.
+	Loading bool
+	// Err is the last lazy load error, shown after the label. A failed
+	// node collapses again, so expanding it retries the load.
+	Err error
+}
+
+// expandable reports whether the node has (or may have) children.
+func (n TreeNode) expandable() bool {
+	return len(n.Children) > 0 || n.HasChildren
+}
+
+// TreeLoadedMsg delivers lazily loaded children (see Tree.Load).
+type TreeLoadedMsg struct {
+	Tree     string // the tree's Key
+	ID       string
+	Children []TreeNode
+	Err      error
+}
+
+// Tree is a hierarchical list with expand/collapse, keyboard navigation,
+// lazy loading, and a scrolling window.
+//
+// The tree is a single focus stop keyed with Key; each visible row is
+// keyed "<Key>-<id>" so ClickMsg identifies the clicked node. Forward
+// key and click messages (and TreeLoadedMsg) to Update.
+type Tree struct {
+	Key      string
+	Roots    []TreeNode
+	Selected string // ID of the selected node
+
+	// Height is the number of visible rows (0 = all). Offset is the
+	// first visible row; Update keeps the selection inside the window.
+	Height int
+	Offset int
+
+	// Load fetches the children of a node with HasChildren the first
+	// time it is expanded. It runs as a Cmd; the result arrives as a
+	// TreeLoadedMsg.
+	Load func(id string) ([]TreeNode, error)
+
+	FG         node.Color
+	GuideFG    node.Color // default 8
+	SelectedFG node.Color
+	SelectedBG node.Color // default 6 while focused
+}
+
+// treeRow is a visible row of the flattened tree.
+type treeRow struct {
+	node   TreeNode
+	parent string // parent ID, "" for roots
+	guide  string // guide-line prefix
+}
+
+// rows flattens the expanded part of the tree in display order.
+func (t Tree) rows() []treeRow {
+	var out []treeRow
+	var walk func(nodes []TreeNode, parent, indent string, root bool)
+	walk = func(nodes []TreeNode, parent, indent string, root bool) {
+		for i, n := range nodes {
+			last := i == len(nodes)-1
+			branch, next := "├─ ", "│  "
+			if last {
+				branch, next = "└─ ", "   "
+			}
+			if root {
+				branch, next = "", ""
+			}
+			out = append(out, treeRow{node: n, parent: parent, guide: indent + branch})
+			if n.Expanded {
+				walk(n.Children, n.ID, indent+next, false)
+			}
+		}
+	}
+	walk(t.Roots, "", "", true)
+	return out
+}
+
+func (t Tree) indexOf(rows []treeRow, id string) int {
+	for i, r := range rows {
+		if r.node.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// SelectedNode returns the selected node.
+func (t Tree) SelectedNode() (TreeNode, bool) {
+	return findTree(t.Roots, t.Selected)
+}
+
+// Update handles navigation keys (when focused is the tree's Key),
+// clicks on rows, and TreeLoadedMsg. The returned Cmd, if any, loads
+// children for a newly expanded lazy node.
+func (t Tree) Update(msg app.Msg, focused string) (Tree, app.Cmd) {
+	var cmd app.Cmd
+	rows := t.rows()
+	cur := t.indexOf(rows, t.Selected)
+
+	switch msg := msg.(type) {
+	case TreeLoadedMsg:
+		if msg.Tree != t.Key {
+			return t, nil
+		}
+		t.Roots = mapTree(t.Roots, msg.ID, func(n *TreeNode) {
+			n.Loading = false
+			n.Err = msg.Err
+			if msg.Err != nil {
+				n.Expanded = false
+				return
+			}
+			n.Children = msg.Children
+			n.HasChildren = len(msg.Children) > 0
+		})
+	case app.ClickMsg:
+		id, ok := strings.CutPrefix(msg.Key, t.Key+"-")
+		if !ok || t.indexOf(rows, id) < 0 {
+			return t, nil
+		}
+		t.Selected = id
+		t, cmd = t.toggle(id)
+	case app.KeyMsg:
+		if focused != t.Key || len(rows) == 0 {
+			return t, nil
+		}
+		if cur < 0 {
+			// Nothing selected yet: the first key just selects the top.
+			t.Selected = rows[0].node.ID
+			break
+		}
+		row := rows[cur]
+		switch msg.Key.Type {
+		case input.Up:
+			cur = max(cur-1, 0)
+		case input.Down:
+			cur = min(cur+1, len(rows)-1)
+		case input.PageUp:
+			cur = max(cur-max(t.Height, 1), 0)
+		case input.PageDown:
+			cur = min(cur+max(t.Height, 1), len(rows)-1)
+		case input.Home:
+			cur = 0
+		case input.End:
+			cur = len(rows) - 1
+		case input.Right:
+			switch {
+			case !row.node.Expanded && row.node.expandable():
+				t, cmd = t.toggle(row.node.ID)
+			case row.node.Expanded && len(row.node.Children) > 0:
+				cur++
+			}
+		case input.Left:
+			if row.node.Expanded {
+				t, cmd = t.toggle(row.node.ID)
+			} else if p := t.indexOf(rows, row.parent); p >= 0 {
+				cur = p
+			}
+		case input.Enter:
+			t, cmd = t.toggle(row.node.ID)
+		case input.RuneKey:
+			if msg.Key.Rune == ' ' {
+				t, cmd = t.toggle(row.node.ID)
+			}
+		}
+		t.Selected = rows[min(cur, len(rows)-1)].node.ID
+	}
+	t.Offset = t.scrollOffset()
+	return t, cmd
+}
+
+// Toggle expands or collapses the node with the given ID. Expanding a
+// lazy node for the first time returns a Cmd that loads its children.
+func (t Tree) Toggle(id string) (Tree, app.Cmd) {
+	t, cmd := t.toggle(id)
+	t.Offset = t.scrollOffset()
+	return t, cmd
+}
+
+func (t Tree) toggle(id string) (Tree, app.Cmd) {
+	var load bool
+	t.Roots = mapTree(t.Roots, id, func(n *TreeNode) {
+		if !n.expandable() {
+			return
+		}
+		n.Expanded = !n.Expanded
+		if n.Expanded && len(n.Children) == 0 && t.Load != nil && !n.Loading {
+			n.Loading = true
+			n.Err = nil
+			load = true
+		}
+	})
+	if !load {
+		return t, nil
+	}
+	loadFn, key := t.Load, t.Key
+	return t, func() app.Msg {
+		children, err := loadFn(id)
+		return TreeLoadedMsg{Tree: key, ID: id, Children: children, Err: err}
+	}
+}
+
+// mapTree returns a copy of nodes with fn applied to the node with the
+// given ID. Only the slices on the path to that node are copied; nodes
+// is returned unchanged when the ID is not found.
+func mapTree(nodes []TreeNode, id string, fn func(*TreeNode)) []TreeNode {
+	if out, ok := mapTreeAt(nodes, id, fn); ok {
+		return out
+	}
+	return nodes
+}
+
+func mapTreeAt(nodes []TreeNode, id string, fn func(*TreeNode)) ([]TreeNode, bool) {
+	for i := range nodes {
+		if nodes[i].ID == id {
+			out := append([]TreeNode(nil), nodes...)
+			fn(&out[i])
+			return out, true
+		}
+		if children, ok := mapTreeAt(nodes[i].Children, id, fn); ok {
+			out := append([]TreeNode(nil), nodes...)
+			out[i].Children = children
+			return out, true
+		}
+	}
+	return nil, false
+}
+
+// findTree returns the node with the given ID.
+func findTree(nodes []TreeNode, id string) (TreeNode, bool) {
+	for _, n := range nodes {
+		if n.ID == id {
+			return n, true
+		}
+		if found, ok := findTree(n.Children, id); ok {
+			return found, true
+		}
+	}
+	return TreeNode{}, false
+}
+
+// scrollOffset returns Offset moved as little as possible to keep the
+// selected row inside the Height-row window.
+func (t Tree) scrollOffset() int {
+	if t.Height <= 0 {
+		return 0
+	}
+	rows := t.rows()
+	off := clampInt(t.Offset, 0, max(len(rows)-t.Height, 0))
+	if cur := t.indexOf(rows, t.Selected); cur >= 0 {
+		if cur < off {
+			off = cur
+		} else if cur >= off+t.Height {
+			off = cur - t.Height + 1
+		}
+	}
+	return off
+}
+
+// Render builds the visible rows as a Column keyed and focusable with
+// Key. Pass the focused key so the selection is highlighted only while
+// the tree has focus.
+func (t Tree) Render(focused string) node.Node {
+	rows := t.rows()
+	start, end := 0, len(rows)
+	if t.Height > 0 {
+		start = clampInt(t.Offset, 0, max(len(rows)-t.Height, 0))
+		end = min(start+t.Height, len(rows))
+	}
+	guideFG := t.GuideFG
+	if guideFG == 0 {
+		guideFG = 8
+	}
+
+	children := make([]node.Node, 0, end-start)
+	for _, r := range rows[start:end] {
+		n := r.node
+		icon := "  "
+		switch {
+		case n.Loading:
+			icon = "… "
+		case n.Expanded:
+			icon = "▾ "
+		case n.expandable():
+			icon = "▸ "
+		}
+		label := n.Label
+		if n.Err != nil {
+			label += " (" + n.Err.Error() + ")"
+		}
+
+		fg, bg, style := t.FG, node.Color(0), node.StyleFlags(0)
+		if n.ID == t.Selected {
+			fg, bg, style = t.SelectedFG, t.SelectedBG, node.Bold
+			if focused == t.Key && bg == 0 {
+				fg, bg = 0, 6
+			}
+		}
+		children = append(children, node.Row(
+			node.TextStyled(r.guide, guideFG, 0, 0).WithNoWrap(),
+			node.TextStyled(icon+label, fg, bg, style).WithNoWrap(),
+		).WithKey(t.Key+"-"+n.ID))
+	}
+	col := node.Column(children...)
+	if t.Key != "" {
+		col = col.WithKey(t.Key).WithFocusable()
+	}
+	return col
+}
@@ -0,0 +1,114 @@
+package component
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/tooeytest"
+)
+
+func testTree() Tree {
+	return Tree{
+		Key: "t",
+		Roots: []TreeNode{
+			{ID: "src", Label: "src", Expanded: true, Children: []TreeNode{
+				{ID: "main", Label: "main.go"},
+				{ID: "lib", Label: "lib", Expanded: true, Children: []TreeNode{
+					{ID: "a", Label: "a.go"},
+				}},
+				{ID: "util", Label: "util.go"},
+			}},
+			{ID: "docs", Label: "docs", HasChildren: true},
+		},
+		Selected: "src",
+	}
+}
+
+func treeKey(typ input.KeyType) app.Msg { return app.KeyMsg{Key: input.Key{Type: typ}} }
+
+func TestTreeRenderGuides(t *testing.T) {
+	tooeytest.AssertFrame(t, testTree().Render(""), 24, 6, `
+		▾ src
+		├─   main.go
+		├─ ▾ lib
+		│  └─   a.go
+		└─   util.go
+		▸ docs`)
+}
+
+func TestTreeNavigation(t *testing.T) {
+	tr := testTree()
+	tr, _ = tr.Update(treeKey(input.Down), "t")
+	tr, _ = tr.Update(treeKey(input.Down), "t")
+	if tr.Selected != "lib" {
+		t.Fatalf("selected = %q, want lib", tr.Selected)
+	}
+	// Left collapses, Left again moves to the parent.
+	tr, _ = tr.Update(treeKey(input.Left), "t")
+	if n, _ := tr.SelectedNode(); n.Expanded {
+		t.Fatal("left should collapse lib")
+	}
+	tr, _ = tr.Update(treeKey(input.Left), "t")
+	if tr.Selected != "src" {
+		t.Fatalf("left on collapsed node should select parent, got %q", tr.Selected)
+	}
+	// Keys are ignored without focus.
+	tr, _ = tr.Update(treeKey(input.Down), "")
+	if tr.Selected != "src" {
+		t.Fatal("tree should ignore keys when not focused")
+	}
+}
+
+func TestTreeClickSelectsAndToggles(t *testing.T) {
+	tr := testTree()
+	tr, _ = tr.Update(app.ClickMsg{Key: "t-lib"}, "t")
+	n, _ := tr.SelectedNode()
+	if tr.Selected != "lib" || n.Expanded {
+		t.Fatalf("click should select and collapse lib: %q %v", tr.Selected, n.Expanded)
+	}
+}
+
+func TestTreeLazyLoad(t *testing.T) {
+	tr := testTree()
+	tr.Selected = "docs"
+	tr.Load = func(id string) ([]TreeNode, error) {
+		return []TreeNode{{ID: id + "/readme", Label: "README.md"}}, nil
+	}
+	tr, cmd := tr.Update(treeKey(input.Right), "t")
+	if cmd == nil {
+		t.Fatal("expanding a lazy node should return a load command")
+	}
+	if n, _ := tr.SelectedNode(); !n.Loading {
+		t.Fatal("node should be loading")
+	}
+	tr, _ = tr.Update(cmd(), "t")
+	tr, _ = tr.Update(treeKey(input.Right), "t")
+	if tr.Selected != "docs/readme" {
+		t.Fatalf("right on expanded node should select first child, got %q", tr.Selected)
+	}
+
+	failing := testTree()
+	failing.Selected = "docs"
+	failing.Load = func(string) ([]TreeNode, error) { return nil, errors.New("denied") }
+	failing, cmd = failing.Update(treeKey(input.Enter), "t")
+	failing, _ = failing.Update(cmd(), "t")
+	if n, _ := failing.SelectedNode(); n.Expanded || n.Err == nil {
+		t.Fatal("failed load should collapse and keep the error")
+	}
+}
+
+func TestTreeScrollKeepsSelectionVisible(t *testing.T) {
+	tr := testTree()
+	tr.Height = 2
+	for range 4 {
+		tr, _ = tr.Update(treeKey(input.Down), "t")
+	}
+	if tr.Selected != "util" || tr.Offset != 3 {
+		t.Fatalf("selected %q offset %d, want util at offset 3", tr.Selected, tr.Offset)
+	}
+	tooeytest.AssertFrame(t, tr.Render("t"), 24, 2, `
+		│  └─   a.go
+		└─   util.go`)
+}
@@ -0,0 +1,430 @@
+package component
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/textwidth"
+)
+
+// Viewport is a scrolling pager for large read-only text. Only the
+// lines inside the window are turned into nodes, so it stays cheap for
+// long files and logs.
+//
+// While focused: j/k or Up/Down scroll a line, PageUp/PageDown (or
+// Space/b) a page, Home/End (or g/G) jump to the ends, "/" starts an
+// incremental search (Enter keeps it, Escape cancels), and n/N move
+// between matches. The mouse wheel scrolls it too.
+type Viewport struct {
+	Key   string
+	Lines []string
+
+	// Offset is the first visible line. Height is the window height,
+	// including the search prompt row while searching. Width is needed
+	// for Wrap.
+	Offset int
+	Height int
+	Width  int
+
+	LineNumbers bool
+	// Wrap breaks lines longer than Width onto continuation rows
+	// instead of clipping them.
+	Wrap bool
+
+	// Query is the search text; matches are highlighted while it is
+	// set. Searching is true while the query is being typed. The search
+	// is case-insensitive unless the query contains upper case.
+	Query     string
+	Searching bool
+
+	FG        node.Color
+	GutterFG  node.Color // default 8
+	MatchBG   node.Color // default 3
+	CurrentBG node.Color // current match, default 11
+
+	// current match position (line, rune column), valid when matched
+	matched             bool
+	matchLine, matchCol int
+	prevQuery           string // query before "/" began, for Escape
+}
+
+// NewViewport creates a viewport showing text.
+func NewViewport(key, text string) Viewport {
+	return Viewport{Key: key, Lines: textwidth.SplitLines(text)}
+}
+
+// SetText replaces the content, keeping the scroll position in range.
+func (v Viewport) SetText(text string) Viewport {
+	return v.SetLines(textwidth.SplitLines(text))
+}
+
+// SetLines replaces the content, keeping the scroll position in range.
+func (v Viewport) SetLines(lines []string) Viewport {
+	v.Lines = lines
+	v.Offset = clampInt(v.Offset, 0, v.maxOffset())
+	return v
+}
+
+// Update handles scrolling and search keys, and mouse wheel scrolling,
+// while focused is the viewport's Key.
+func (v Viewport) Update(msg app.Msg, focused string) Viewport {
+	switch msg := msg.(type) {
+	case app.ScrollMsg:
+		if focused == v.Key {
+			return v.ScrollBy(-msg.Delta)
+		}
+	case app.PasteMsg:
+		if v.Searching && focused == v.Key {
+			return v.setQuery(v.Query + strings.ReplaceAll(msg.Text, "\n", " "))
+		}
+	case app.KeyMsg:
+		if focused != v.Key {
+			break
+		}
+		if v.Searching {
+			return v.searchKey(msg.Key)
+		}
+		page := max(v.bodyHeight()-1, 1)
+		k := msg.Key
+		switch k.Type {
+		case input.Up:
+			return v.ScrollBy(-1)
+		case input.Down:
+			return v.ScrollBy(1)
+		case input.PageUp:
+			return v.ScrollBy(-page)
+		case input.PageDown:
+			return v.ScrollBy(page)
+		case input.Home:
+			return v.GotoTop()
+		case input.End:
+			return v.GotoBottom()
+		case input.RuneKey:
+			switch k.Rune {
+			case 'k':
+				return v.ScrollBy(-1)
+			case 'j':
+				return v.ScrollBy(1)
+			case 'b':
+				return v.ScrollBy(-page)
+			case ' ':
+				return v.ScrollBy(page)
+			case 'g':
+				return v.GotoTop()
+			case 'G':
+				return v.GotoBottom()
+			case '/':
+				v.Searching = true
+				v.prevQuery = v.Query
+				v.Query = ""
+				v.matched = false
+			case 'n':
+				return v.NextMatch()
+			case 'N':
+				return v.PrevMatch()
+			}
+		}
+	}
+	return v
+}
+
+func (v Viewport) searchKey(k input.Key) Viewport {
+	switch k.Type {
+	case input.RuneKey:
+		return v.setQuery(v.Query + string(k.Rune))
+	case input.Backspace:
+		if r := []rune(v.Query); len(r) > 0 {
+			return v.setQuery(string(r[:len(r)-1]))
+		}
+	case input.Enter:
+		v.Searching = false
+	case input.Escape:
+		v.Searching = false
+		return v.setQuery(v.prevQuery)
+	}
+	return v
+}
+
+// setQuery changes the search and jumps to the first match at or after
+// the top of the window.
+func (v Viewport) setQuery(q string) Viewport {
+	v.Query = q
+	v.matched = false
+	return v.NextMatch()
+}
+
+// ScrollBy scrolls by n lines (negative is up).
+func (v Viewport) ScrollBy(n int) Viewport {
+	v.Offset = clampInt(v.Offset+n, 0, v.maxOffset())
+	return v
+}
+
+// GotoTop scrolls to the first line.
+func (v Viewport) GotoTop() Viewport {
+	v.Offset = 0
+	return v
+}
+
+// GotoBottom scrolls so the last line is at the bottom of the window.
+func (v Viewport) GotoBottom() Viewport {
+	v.Offset = v.maxOffset()
+	return v
+}
+
+// ScrollPercent returns how far the view is scrolled, from 0 (top) to
+// 100 (bottom). Content that fits entirely reports 100.
+func (v Viewport) ScrollPercent() int {
+	maxOff := v.maxOffset()
+	if maxOff == 0 {
+		return 100
+	}
+	return v.Offset * 100 / maxOff
+}
+
+// AtBottom reports whether the last line is visible.
+func (v Viewport) AtBottom() bool {
+	return v.Offset >= v.maxOffset()
+}
+
+// bodyHeight is the number of rows available for content.
+func (v Viewport) bodyHeight() int {
+	h := v.Height
+	if v.Searching {
+		h--
+	}
+	return max(h, 0)
+}
+
+// maxOffset is the largest Offset that still fills the window. With
+// Wrap it accounts for the rows the last lines wrap onto.
+func (v Viewport) maxOffset() int {
+	h := v.bodyHeight()
+	if h == 0 {
+		return max(len(v.Lines)-1, 0)
+	}
+	if !v.Wrap || v.contentWidth() <= 0 {
+		return max(len(v.Lines)-h, 0)
+	}
+	rows := 0
+	for i := len(v.Lines) - 1; i >= 0; i-- {
+		rows += len(v.wrapLine(v.Lines[i]))
+		if rows > h {
+			return i + 1
+		}
+		if rows == h {
+			return i
+		}
+	}
+	return 0
+}
+
+// gutterWidth is the width of the line-number column, including the
+// space after the numbers.
+func (v Viewport) gutterWidth() int {
+	if !v.LineNumbers {
+		return 0
+	}
+	return len(strconv.Itoa(len(v.Lines))) + 1
+}
+
+func (v Viewport) contentWidth() int {
+	return v.Width - v.gutterWidth()
+}
+
+// wrapLine splits a line into row-sized rune ranges [start, end).
+func (v Viewport) wrapLine(line string) [][2]int {
+	runes := []rune(line)
+	w := v.contentWidth()
+	if !v.Wrap || w <= 0 {
+		return [][2]int{{0, len(runes)}}
+	}
+	var rows [][2]int
+	start, cw := 0, 0
+	for i, r := range runes {
+		rw := textwidth.Rune(r)
+		if cw+rw > w && i > start {
+			rows = append(rows, [2]int{start, i})
+			start, cw = i, 0
+		}
+		cw += rw
+	}
+	return append(rows, [2]int{start, len(runes)})
+}
+
+// NextMatch scrolls to the next match after the current one, wrapping
+// around at the end.
+func (v Viewport) NextMatch() Viewport {
+	return v.findMatch(1)
+}
+
+// PrevMatch scrolls to the previous match, wrapping around at the top.
+func (v Viewport) PrevMatch() Viewport {
+	return v.findMatch(-1)
+}
+
+func (v Viewport) findMatch(dir int) Viewport {
+	if v.Query == "" || len(v.Lines) == 0 {
+		return v
+	}
+	line, col := v.matchLine, v.matchCol
+	if !v.matched {
+		line, col = clampInt(v.Offset, 0, len(v.Lines)-1), -1
+		if dir < 0 {
+			col = len([]rune(v.Lines[line])) + 1
+		}
+	}
+	for i := 0; i <= len(v.Lines); i++ {
+		l := ((line+dir*i)%len(v.Lines) + len(v.Lines)) % len(v.Lines)
+		ms := v.matchesIn(v.Lines[l])
+		if dir < 0 {
+			for j := len(ms) - 1; j >= 0; j-- {
+				if i > 0 || ms[j][0] < col {
+					return v.showMatch(l, ms[j][0])
+				}
+			}
+			continue
+		}
+		for _, m := range ms {
+			if i > 0 || m[0] > col {
+				return v.showMatch(l, m[0])
+			}
+		}
+	}
+	return v
+}
+
+// showMatch makes the match at (line, col) current and scrolls it into
+// view, leaving the window alone when it is already visible.
+func (v Viewport) showMatch(line, col int) Viewport {
+	v.matched, v.matchLine, v.matchCol = true, line, col
+	h := v.bodyHeight()
+	if line < v.Offset || (h > 0 && line >= v.Offset+h) {
+		v.Offset = clampInt(line-h/2, 0, v.maxOffset())
+	}
+	return v
+}
+
+// MatchCount returns the number of matches of Query in the content.
+func (v Viewport) MatchCount() int {
+	if v.Query == "" {
+		return 0
+	}
+	n := 0
+	for _, l := range v.Lines {
+		n += len(v.matchesIn(l))
+	}
+	return n
+}
+
+// matchesIn returns the rune ranges of Query in line.
+func (v Viewport) matchesIn(line string) [][2]int {
+	q := []rune(v.Query)
+	if len(q) == 0 {
+		return nil
+	}
+	fold := strings.ToLower(v.Query) == v.Query
+	runes := []rune(line)
+	var out [][2]int
+	for i := 0; i+len(q) <= len(runes); i++ {
+		ok := true
+		for j, r := range q {
+			c := runes[i+j]
+			if fold {
+				c = unicode.ToLower(c)
+			}
+			if c != r {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			out = append(out, [2]int{i, i + len(q)})
+			i += len(q) - 1
+		}
+	}
+	return out
+}
+
+// Render builds the visible window as a Column keyed and focusable with
+// Key, followed by the search prompt while searching.
+func (v Viewport) Render(focused string) node.Node {
+	h := v.bodyHeight()
+	gutterFG := v.GutterFG
+	if gutterFG == 0 {
+		gutterFG = 8
+	}
+	gw := v.gutterWidth()
+
+	rows := make([]node.Node, 0, h+1)
+	for i := clampInt(v.Offset, 0, len(v.Lines)); i < len(v.Lines) && (h == 0 || len(rows) < h); i++ {
+		runes := []rune(v.Lines[i])
+		matches := v.matchesIn(v.Lines[i])
+		for j, span := range v.wrapLine(v.Lines[i]) {
+			if h > 0 && len(rows) >= h {
+				break
+			}
+			var segs []node.Node
+			if gw > 0 {
+				num := ""
+				if j == 0 {
+					num = strconv.Itoa(i + 1)
+				}
+				num = strings.Repeat(" ", gw-1-len(num)) + num + " "
+				segs = append(segs, node.TextStyled(num, gutterFG, 0, 0).WithNoWrap())
+			}
+			segs = append(segs, v.renderSpan(runes, span, i, matches)...)
+			rows = append(rows, node.Row(segs...))
+		}
+	}
+	if v.Searching {
+		for h > 0 && len(rows) < h {
+			rows = append(rows, node.Text(""))
+		}
+		rows = append(rows, node.Row(
+			node.TextStyled("/"+v.Query, v.FG, 0, 0).WithNoWrap(),
+			node.TextStyled(" ", node.Color(0), node.Color(15), 0),
+		))
+	}
+
+	col := node.Column(rows...)
+	if v.Key != "" {
+		col = col.WithKey(v.Key).WithFocusable()
+	}
+	return col
+}
+
+// renderSpan renders runes[span] of line i, highlighting matches.
+func (v Viewport) renderSpan(runes []rune, span [2]int, line int, matches [][2]int) []node.Node {
+	matchBG, currentBG := v.MatchBG, v.CurrentBG
+	if matchBG == 0 {
+		matchBG = 3
+	}
+	if currentBG == 0 {
+		currentBG = 11
+	}
+	var segs []node.Node
+	pos := span[0]
+	for _, m := range matches {
+		s, e := max(m[0], span[0]), min(m[1], span[1])
+		if s >= e {
+			continue
+		}
+		if s > pos {
+			segs = append(segs, node.TextStyled(string(runes[pos:s]), v.FG, 0, 0).WithNoWrap())
+		}
+		bg := matchBG
+		if v.matched && line == v.matchLine && m[0] == v.matchCol {
+			bg = currentBG
+		}
+		segs = append(segs, node.TextStyled(string(runes[s:e]), 0, bg, 0).WithNoWrap())
+		pos = e
+	}
+	if pos < span[1] || len(segs) == 0 {
+		segs = append(segs, node.TextStyled(string(runes[pos:span[1]]), v.FG, 0, 0).WithNoWrap())
+	}
+	return segs
+}
@@ -0,0 +1,132 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stukennedy/tooey/app"
+	"github.com/stukennedy/tooey/input"
+	"github.com/stukennedy/tooey/tooeytest"
+)
+
+func numberedText(n int) string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func vpKey(v Viewport, keys ...input.Key) Viewport {
+	for _, k := range keys {
+		v = v.Update(app.KeyMsg{Key: k}, v.Key)
+	}
+	return v
+}
+
+func runeKey(r rune) input.Key { return input.Key{Type: input.RuneKey, Rune: r} }
+
+func TestViewportScrolling(t *testing.T) {
+	v := NewViewport("vp", numberedText(100))
+	v.Height = 10
+	v = vpKey(v, runeKey('j'), runeKey('j'))
+	if v.Offset != 2 {
+		t.Fatalf("offset = %d, want 2", v.Offset)
+	}
+	v = vpKey(v, input.Key{Type: input.PageDown})
+	if v.Offset != 11 {
+		t.Fatalf("page down offset = %d, want 11", v.Offset)
+	}
+	v = vpKey(v, runeKey('G'))
+	if v.Offset != 90 || v.ScrollPercent() != 100 || !v.AtBottom() {
+		t.Fatalf("G: offset %d, percent %d", v.Offset, v.ScrollPercent())
+	}
+	v = v.Update(app.ScrollMsg{Delta: 3}, "other")
+	if v.Offset != 90 {
+		t.Fatalf("wheel scrolled an unfocused viewport to %d", v.Offset)
+	}
+	v = v.Update(app.ScrollMsg{Delta: 3}, v.Key)
+	if v.Offset != 87 {
+		t.Fatalf("wheel up offset = %d, want 87", v.Offset)
+	}
+	v = vpKey(v, input.Key{Type: input.Home})
+	if v.ScrollPercent() != 0 {
+		t.Fatalf("home percent = %d", v.ScrollPercent())
+	}
+	// Only the window is materialized.
+	if n := len(v.Render("vp").Children); n != 10 {
+		t.Fatalf("rendered %d rows, want 10", n)
+	}
+}
+
+func TestViewportLineNumbersAndWrap(t *testing.T) {
+	v := NewViewport("vp", "short\nabcdefghij\nend")
+	v.Height = 5
+	v.Width = 8
+	v.LineNumbers = true
+	v.Wrap = true
+	tooeytest.AssertFrame(t, v.Render(""), 8, 5, `
+		1 short
+		2 abcdef
+		  ghij
+		3 end`)
+
+	v.Wrap = false
+	tooeytest.AssertFrame(t, v.Render(""), 8, 5, `
+		1 short
+		2 abcdef
+		3 end`)
+}
+
+func TestViewportSearch(t *testing.T) {
+	v := NewViewport("vp", numberedText(50))
+	v.Height = 5
+	v = vpKey(v, runeKey('/'), runeKey('4'), runeKey('2'))
+	if !v.Searching || v.Query != "42" {
+		t.Fatalf("searching %v query %q", v.Searching, v.Query)
+	}
+	if v.Offset > 41 || v.Offset+4 <= 41 {
+		t.Fatalf("match line 42 not in view, offset %d", v.Offset)
+	}
+	v = vpKey(v, input.Key{Type: input.Enter})
+	if v.Searching {
+		t.Fatal("enter should end query entry")
+	}
+
+	v = v.GotoTop()
+	v = vpKey(v, runeKey('/'), runeKey('4'), input.Key{Type: input.Enter})
+	if v.MatchCount() != 15 { // 4, 14, 24, 34, 40..49, and 44 twice
+		t.Fatalf("match count = %d, want 15", v.MatchCount())
+	}
+	v = vpKey(v, runeKey('n'), runeKey('n'))
+	if v.matchLine != 23 {
+		t.Fatalf("after n n, current match on line %d, want index 23", v.matchLine)
+	}
+	v = vpKey(v, runeKey('N'))
+	if v.matchLine != 13 {
+		t.Fatalf("after N, current match on line %d, want index 13", v.matchLine)
+	}
+
+	// Escape restores the previous query.
+	v = vpKey(v, runeKey('/'), runeKey('x'), input.Key{Type: input.Escape})
+	if v.Query != "4" || v.Searching {
+		t.Fatalf("escape: query %q searching %v", v.Query, v.Searching)
+	}
+}
+
+func TestViewportMatchHighlight(t *testing.T) {
+	v := NewViewport("vp", "foo bar foo")
+	v.Height = 1
+	v = vpKey(v, runeKey('/'), runeKey('f'), runeKey('o'), runeKey('o'), input.Key{Type: input.Enter})
+	buf := tooeytest.Render(v.Render("vp"), 11, 1)
+	if buf.Get(0, 0).BG != 11 {
+		t.Fatalf("current match BG = %d, want 11", buf.Get(0, 0).BG)
+	}
+	if buf.Get(8, 0).BG != 3 {
+		t.Fatalf("other match BG = %d, want 3", buf.Get(8, 0).BG)
+	}
+	if buf.Get(4, 0).BG != 0 {
+		t.Fatal("non-match should not be highlighted")
+	}
+}
@@ -124,6 +124,9 @@ func main() {
 		},
 		Update: maudeUpdate,
 		View:   maudeView,
+		// Ask the terminal for synchronized output so streamed replies
+		// don't tear.
+		QueryTerminal: true,
 	}
 
 	if err := a.Run(context.Background()); err != nil && err != context.Canceled {
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stukennedy/tooey/ansi"
+	"github.com/stukennedy/tooey/cell"
+	"github.com/stukennedy/tooey/component"
+	"github.com/stukennedy/tooey/diff"
+	"github.com/stukennedy/tooey/tooeytest"
+)
+
+// frames renders a session of the demo at 160×50: typing a prompt, the
+// token count ticking while thinking, then the canned replies arriving.
+func frames() []*cell.Buffer {
+	const w, h = 160, 50
+	mdl := &maudeModel{
+		width:  w,
+		height: h,
+		input:  component.NewTextInput("Send a message..."),
+		messages: []chatMessage{
+			{Role: roleAssistant, Text: "Hello! I'm Maude Code, your AI coding assistant. How can I help you today?"},
+		},
+		tokenCount: 42,
+		cost:       0.001,
+	}
+	var out []*cell.Buffer
+	frame := func() { out = append(out, tooeytest.Render(maudeView(mdl, ""), w, h)) }
+
+	for _, r := range "fix the greeting" {
+		mdl.input = mdl.input.Paste(string(r))
+		frame()
+	}
+	_, mdl.input = mdl.input.Submit()
+	mdl.messages = append(mdl.messages, chatMessage{Role: roleUser, Text: "fix the greeting"})
+	mdl.thinking = true
+	for range 10 {
+		mdl.tokenCount += 17
+		frame()
+	}
+	mdl.thinking = false
+	for _, reply := range cannedResponses {
+		mdl.messages = append(mdl.messages, reply)
+		frame()
+	}
+	return out
+}
+
+func benchmarkDiff(b *testing.B, diffFrame func(prev, next *cell.Buffer) []diff.Change) {
+	fs := frames()
+	var changes, cells int
+	for b.Loop() {
+		changes, cells = 0, 0
+		for i := 1; i < len(fs); i++ {
+			for _, ch := range diffFrame(fs[i-1], fs[i]) {
+				changes++
+				cells += len(ch.Cells)
+			}
+		}
+	}
+	n := float64(len(fs) - 1)
+	b.ReportMetric(float64(changes)/n, "changes/frame")
+	b.ReportMetric(float64(cells)/n, "cells/frame")
+}
+
+func BenchmarkDiff(b *testing.B) { benchmarkDiff(b, diff.Diff) }
+
+func BenchmarkDiffer(b *testing.B) {
+	var d diff.Differ
+	benchmarkDiff(b, d.Diff)
+}
+
+func benchmarkRender(b *testing.B, scroll bool) {
+	fs := frames()
+	var n int
+	for b.Loop() {
+		var d diff.Differ
+		r := ansi.NewRenderer(fs[0].Width, fs[0].Height)
+		var out bytes.Buffer
+		for i := 1; i < len(fs); i++ {
+			if scroll {
+				s, changes := d.DiffScroll(fs[i-1], fs[i])
+				r.RenderScroll(&out, s, changes)
+			} else {
+				r.Render(&out, d.Diff(fs[i-1], fs[i]))
+			}
+		}
+		n = out.Len()
+	}
+	b.ReportMetric(float64(n)/float64(len(fs)-1), "bytes/frame")
+}
+
+func BenchmarkRender(b *testing.B)       { benchmarkRender(b, false) }
+func BenchmarkRenderScroll(b *testing.B) { benchmarkRender(b, true) }
@@ -1,59 +1,297 @@
 package diff
 
-import "github.com/stukennedy/tooey/cell"
+import (
+	"hash/maphash"
+
+	"github.com/stukennedy/tooey/cell"
+)
 
 // Change represents a horizontal run of changed cells at a position.
+// Cells alias the next buffer passed to Diff.
 type Change struct {
 	X, Y  int
 	Cells []cell.Cell
 }
 
+// maxGap is the most unchanged cells merged into a run to join it with
+// the next one. Re-emitting a few cells in the same style is cheaper
+// than the cursor move between two runs (at least four bytes).
+const maxGap = 3
+
 // Diff compares two buffers and returns the minimal set of changes.
 // Both buffers must have the same dimensions.
 func Diff(prev, next *cell.Buffer) []Change {
 	if prev.Width != next.Width || prev.Height != next.Height {
 		// Full redraw if sizes differ
-		return fullRedraw(next)
+		return fullRedraw(nil, next)
 	}
-
 	var changes []Change
-	w := next.Width
+	for y := 0; y < next.Height; y++ {
+		changes = diffRow(changes, prev, next, y)
+	}
+	return changes
+}
+
+// Differ diffs successive frames. It reuses its change slice from call
+// to call, and remembers a hash of each row of the last frame so rows
+// that hash the same in the next frame are skipped without comparing
+// their cells. The zero value is ready to use.
+type Differ struct {
+	changes []Change
+	last    *cell.Buffer // next from the previous call
+	hashes  []uint64     // last's row hashes
+	scratch []uint64
+
+	// For scroll detection: prev's row hashes when it isn't last, and
+	// prev as it looks after the scroll.
+	prevHashes    []uint64
+	shifted       cell.Buffer
+	shiftedHashes []uint64
+}
+
+// Scroll moves rows Top through Bottom up by N rows, or down by -N
+// when N is negative. Rows scrolled in are blank. N is 0 for no scroll.
+type Scroll struct {
+	Top, Bottom int
+	N           int
+}
+
+// minScrollGain is the fewest changed rows a scroll must fix to be
+// worth its escape sequences.
+const minScrollGain = 2
+
+// Diff is like the package-level Diff, but the returned changes are
+// only valid until the next call. Row hashes are reused when prev is
+// the next buffer of the previous call, so neither buffer may be
+// modified in between.
+func (d *Differ) Diff(prev, next *cell.Buffer) []Change {
+	_, changes := d.diff(prev, next, false)
+	return changes
+}
 
+// DiffScroll is like Diff, but first looks for a block of rows that
+// moved up or down between the frames, as when a log gains a line. If
+// moving it saves enough, DiffScroll returns the Scroll that moves it
+// and the changes against prev as it looks after that scroll.
+func (d *Differ) DiffScroll(prev, next *cell.Buffer) (Scroll, []Change) {
+	return d.diff(prev, next, true)
+}
+
+func (d *Differ) diff(prev, next *cell.Buffer, detect bool) (Scroll, []Change) {
+	var scroll Scroll
+	changes := d.changes[:0]
+	hashes := d.scratch[:0]
 	for y := 0; y < next.Height; y++ {
-		var run []cell.Cell
-		runStart := 0
+		hashes = append(hashes, rowHash(row(next, y)))
+	}
+
+	prevHashes := d.hashes
+	if prev != d.last || len(prevHashes) != len(hashes) {
+		prevHashes = nil
+	}
+	if detect && prevHashes == nil && prev.Width == next.Width && prev.Height == next.Height {
+		prevHashes = d.prevHashes[:0]
+		for y := 0; y < prev.Height; y++ {
+			prevHashes = append(prevHashes, rowHash(row(prev, y)))
+		}
+		d.prevHashes = prevHashes
+	}
+	if detect && prevHashes != nil {
+		if scroll = findScroll(prevHashes, hashes); scroll.N != 0 {
+			prev, prevHashes = d.applyScroll(prev, prevHashes, scroll)
+		}
+	}
+
+	switch {
+	case prev.Width != next.Width || prev.Height != next.Height:
+		changes = fullRedraw(changes, next)
+	case prevHashes != nil:
+		for y, h := range hashes {
+			if h != prevHashes[y] {
+				changes = diffRow(changes, prev, next, y)
+			}
+		}
+	default:
+		for y := 0; y < next.Height; y++ {
+			changes = diffRow(changes, prev, next, y)
+		}
+	}
 
-		for x := 0; x < w; x++ {
-			pi := y*w + x
-			pc := prev.Cells[pi]
-			nc := next.Cells[pi]
+	d.changes, d.last = changes, next
+	d.hashes, d.scratch = hashes, d.hashes
+	return scroll, changes
+}
 
-			if pc != nc {
-				if run == nil {
-					runStart = x
+// findScroll finds the block of rows whose move fixes the most changed
+// rows: a run of next rows y that equal prev rows y+s for one shift s,
+// preferring smaller shifts. Rows are compared by rowHash, so a
+// collision would move the wrong row as well as skip it. Rows the scroll blanks at the end of the
+// block that were already right count against it. It returns the zero
+// Scroll when no block gains minScrollGain rows.
+func findScroll(prev, next []uint64) Scroll {
+	var best Scroll
+	bestGain := minScrollGain - 1
+	h := len(next)
+	changed := 0
+	for y := range h {
+		if next[y] != prev[y] {
+			changed++
+		}
+	}
+	if changed < minScrollGain {
+		return best
+	}
+	for k := 1; k < h; k++ {
+		for _, s := range [2]int{k, -k} {
+			start, gain := -1, 0
+			for y := 0; y <= h; y++ {
+				if y < h && y+s >= 0 && y+s < h && next[y] == prev[y+s] {
+					if start < 0 {
+						start, gain = y, 0
+					}
+					if next[y] != prev[y] {
+						gain++
+					}
+					continue
 				}
-				run = append(run, nc)
-			} else if run != nil {
-				changes = append(changes, Change{X: runStart, Y: y, Cells: run})
-				run = nil
+				if start >= 0 && gain > bestGain {
+					// Next rows start..y-1 came from start+s..y-1+s.
+					sc := Scroll{Top: min(start, start+s), Bottom: max(y-1, y-1+s), N: s}
+					if gain -= blanked(prev, next, sc); gain > bestGain {
+						bestGain, best = gain, sc
+					}
+				}
+				start = -1
 			}
 		}
-		if run != nil {
-			changes = append(changes, Change{X: runStart, Y: y, Cells: run})
+	}
+	return best
+}
+
+// blanked counts the rows sc scrolls blank in that were unchanged
+// between prev and next, and so must be redrawn only because of it.
+func blanked(prev, next []uint64, sc Scroll) int {
+	from, to := sc.Bottom-sc.N+1, sc.Bottom // scrolled up: rows at the bottom
+	if sc.N < 0 {
+		from, to = sc.Top, sc.Top-sc.N-1
+	}
+	n := 0
+	for y := from; y <= to; y++ {
+		if next[y] == prev[y] {
+			n++
+		}
+	}
+	return n
+}
+
+// applyScroll returns prev and its row hashes as they look after s,
+// in buffers the Differ reuses.
+func (d *Differ) applyScroll(prev *cell.Buffer, hashes []uint64, s Scroll) (*cell.Buffer, []uint64) {
+	sh := &d.shifted
+	sh.Width, sh.Height = prev.Width, prev.Height
+	sh.Cells = append(sh.Cells[:0], prev.Cells...)
+	shifted := append(d.shiftedHashes[:0], hashes...)
+	d.shiftedHashes = shifted
+	var blankHash uint64
+	for y := s.Top; y <= s.Bottom; y++ {
+		dst := row(sh, y)
+		if src := y + s.N; src >= s.Top && src <= s.Bottom {
+			copy(dst, row(prev, src))
+			shifted[y] = hashes[src]
+			continue
+		}
+		for x := range dst {
+			dst[x] = cell.Cell{Rune: ' '}
 		}
+		if blankHash == 0 {
+			blankHash = rowHash(dst)
+		}
+		shifted[y] = blankHash
 	}
+	return sh, shifted
+}
 
+// diffRow appends the changed runs of row y to changes. Runs separated
+// by at most maxGap unchanged cells in the same style are merged.
+func diffRow(changes []Change, prev, next *cell.Buffer, y int) []Change {
+	p, n := row(prev, y), row(next, y)
+	w := len(n)
+	for x := 0; x < w; {
+		if p[x] == n[x] {
+			x++
+			continue
+		}
+		start, end := x, x+1
+		for end < w {
+			if p[end] != n[end] {
+				end++
+				continue
+			}
+			gap := end
+			for gap < w && gap-end <= maxGap && p[gap] == n[gap] {
+				gap++
+			}
+			if gap == w || gap-end > maxGap || !sameStyle(n[end:gap], n[end-1]) {
+				break
+			}
+			end = gap
+		}
+		changes = append(changes, Change{X: start, Y: y, Cells: n[start:end]})
+		x = end
+	}
 	return changes
 }
 
-func fullRedraw(buf *cell.Buffer) []Change {
-	changes := make([]Change, buf.Height)
+// sameStyle reports whether cells are all drawn like c, so writing
+// them out needs no SGR change.
+func sameStyle(cells []cell.Cell, c cell.Cell) bool {
+	for _, g := range cells {
+		if g.FG != c.FG || g.BG != c.BG || g.UL != c.UL || g.Style != c.Style || g.Link != c.Link {
+			return false
+		}
+	}
+	return true
+}
+
+func row(buf *cell.Buffer, y int) []cell.Cell {
+	return buf.Cells[y*buf.Width : (y+1)*buf.Width]
+}
+
+func fullRedraw(changes []Change, buf *cell.Buffer) []Change {
 	for y := 0; y < buf.Height; y++ {
-		start := y * buf.Width
-		end := start + buf.Width
-		row := make([]cell.Cell, buf.Width)
-		copy(row, buf.Cells[start:end])
-		changes[y] = Change{X: 0, Y: y, Cells: row}
+		changes = append(changes, Change{X: 0, Y: y, Cells: row(buf, y)})
 	}
 	return changes
 }
+
+var linkSeed = maphash.MakeSeed()
+
+// rowHash hashes a row of cells with two interleaved lanes to keep the
+// multiplies from serializing. Each word mixed in packs fields into
+// disjoint bits, so a change to one field can't cancel out a change to
+// another, and each step folds the high bits of the product back down
+// so a change in a word's high bits still reaches every bit of the
+// hash. Equal hashes are taken to mean equal rows.
+func rowHash(cells []cell.Cell) uint64 {
+	const prime = 1099511628211
+	h1 := uint64(14695981039346656037)
+	h2 := h1 ^ uint64(len(cells))
+	for _, c := range cells {
+		h1 = (h1 ^ (uint64(c.Rune) | uint64(c.Style)<<32)) * prime
+		h1 ^= h1 >> 29
+		h2 = (h2 ^ (uint64(c.FG) | uint64(c.BG)<<32)) * prime
+		h2 ^= h2 >> 29
+		// Underline colors are rare, so they only cost a step when set;
+		// bit 48 (above Style) keeps the word apart from a rune's.
+		if c.UL != 0 {
+			h1 = (h1 ^ uint64(c.UL) ^ 1<<48) * prime
+			h1 ^= h1 >> 29
+		}
+		if c.Link != "" {
+			h2 = (h2 ^ maphash.String(linkSeed, c.Link)) * prime
+			h2 ^= h2 >> 29
+		}
+	}
+	h := h1 ^ h2*prime
+	return h ^ h>>32
+}
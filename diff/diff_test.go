@@ -1,9 +1,12 @@
 package diff
 
 import (
+	"math/rand"
+	"reflect"
 	"testing"
 
 	"github.com/stukennedy/tooey/cell"
+	"github.com/stukennedy/tooey/node"
 )
 
 func TestIdenticalBuffers(t *testing.T) {
@@ -50,9 +53,267 @@ func TestSplitChanges(t *testing.T) {
 	a := cell.NewBuffer(10, 1)
 	b := cell.NewBuffer(10, 1)
 	b.Set(1, 0, cell.Cell{Rune: 'A'})
-	b.Set(5, 0, cell.Cell{Rune: 'B'})
+	b.Set(6, 0, cell.Cell{Rune: 'B'})
 	changes := Diff(a, b)
 	if len(changes) != 2 {
 		t.Fatalf("expected 2 changes, got %d", len(changes))
 	}
 }
+
+func TestLinkChangeIsCellChange(t *testing.T) {
+	a := cell.NewBuffer(5, 1)
+	b := cell.NewBuffer(5, 1)
+	a.WriteString(0, 0, "go", 0, 0, 0)
+	b.WriteString(0, 0, "go", 0, 0, 0)
+	b.Set(1, 0, cell.Cell{Rune: 'o', Link: "https://go.dev"})
+	changes := Diff(a, b)
+	if len(changes) != 1 || changes[0].X != 1 || changes[0].Cells[0].Link != "https://go.dev" {
+		t.Fatalf("expected the relinked cell as a change, got %+v", changes)
+	}
+}
+
+func TestCoalesceShortGaps(t *testing.T) {
+	a := cell.NewBuffer(20, 1)
+	b := cell.NewBuffer(20, 1)
+	b.Set(1, 0, cell.Cell{Rune: 'A'})
+	b.Set(5, 0, cell.Cell{Rune: 'B'}) // three plain cells between: merged
+	b.Set(9, 0, cell.Cell{Rune: 'C'})
+	a.Set(7, 0, cell.Cell{Rune: ' ', FG: 3}) // styled gap cell: kept apart
+	b.Set(7, 0, cell.Cell{Rune: ' ', FG: 3})
+	changes := Diff(a, b)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if changes[0].X != 1 || len(changes[0].Cells) != 5 || changes[0].Cells[4].Rune != 'B' {
+		t.Fatalf("gap not merged: %+v", changes[0])
+	}
+	if changes[1].X != 9 || len(changes[1].Cells) != 1 {
+		t.Fatalf("styled gap merged: %+v", changes[1])
+	}
+}
+
+func TestDifferMatchesDiff(t *testing.T) {
+	const w, h = 16, 6
+	rng := rand.New(rand.NewSource(1))
+	var d Differ
+	prev := cell.NewBuffer(w, h)
+	for i := range 100 {
+		next := cell.NewBuffer(w, h)
+		copy(next.Cells, prev.Cells)
+		for range rng.Intn(6) {
+			next.Set(rng.Intn(w), rng.Intn(h), cell.Cell{Rune: 'a' + rune(rng.Intn(3)), FG: node.Color(rng.Intn(2))})
+		}
+		if i%10 == 0 {
+			next.Set(0, 0, cell.Cell{Rune: 'x', Link: "https://example.com/" + string(rune('a'+i%26))})
+		}
+		want := Diff(prev, next)
+		got := d.Diff(prev, next)
+		if !reflect.DeepEqual(got, want) && (len(got) != 0 || len(want) != 0) {
+			t.Fatalf("frame %d: Differ %+v, Diff %+v", i, got, want)
+		}
+		prev = next
+	}
+}
+
+func TestDifferSkipsUnchangedRows(t *testing.T) {
+	var d Differ
+	a := cell.NewBuffer(10, 3)
+	b := cell.NewBuffer(10, 3)
+	b.WriteString(0, 1, "hello", 0, 0, 0)
+	d.Diff(a, b)
+
+	// Rows whose hash matches aren't compared at all: a cell changed
+	// behind the Differ's back in an otherwise identical row goes
+	// unnoticed, which shows the row was skipped.
+	c := cell.NewBuffer(10, 3)
+	c.WriteString(0, 1, "hello", 0, 0, 0)
+	c.WriteString(0, 2, "x", 0, 0, 0)
+	b.Cells[0].Rune = 'z'
+	changes := d.Diff(b, c)
+	if len(changes) != 1 || changes[0].Y != 2 {
+		t.Fatalf("expected only row 2, got %+v", changes)
+	}
+}
+
+func TestDifferSeesStyleForColorSwaps(t *testing.T) {
+	// Each pair differs only in how its bits split between fields, so
+	// a hash that packs fields into overlapping bits can't tell them
+	// apart.
+	pairs := [][2]cell.Cell{
+		{{Rune: 'x', Style: node.DottedUnderline}, {Rune: 'x', FG: 1}},
+		{{Rune: 'x', Style: node.DashedUnderline}, {Rune: 'x', FG: 2}},
+		{{Rune: 'x', Style: node.Bold}, {Rune: 'x', BG: 1}},
+		{{Rune: 'x', FG: 1}, {Rune: 'x', BG: 1}},
+		{{Rune: 'x', UL: 1}, {Rune: 'x', FG: 1}},
+	}
+	for _, p := range pairs {
+		var d Differ
+		a := cell.NewBuffer(4, 1)
+		b := cell.NewBuffer(4, 1)
+		a.Set(1, 0, p[0])
+		b.Set(1, 0, p[1])
+		d.Diff(cell.NewBuffer(4, 1), a)
+		if changes := d.Diff(a, b); len(changes) != 1 {
+			t.Errorf("%+v to %+v: got %d changes, want 1", p[0], p[1], len(changes))
+		}
+	}
+}
+
+func TestDifferSeesHighBitChanges(t *testing.T) {
+	// Flipping the same high bit in two cells cancels out in a hash
+	// whose multiplies only carry upward.
+	fill := func(b *cell.Buffer) {
+		for x := range b.Width {
+			b.Set(x, 0, cell.Cell{Rune: ' ', BG: node.RGB(0, 0, 0)})
+		}
+	}
+	a := cell.NewBuffer(80, 1)
+	b := cell.NewBuffer(80, 1)
+	fill(a)
+	fill(b)
+	for _, x := range []int{2, 54} {
+		b.Set(x, 0, cell.Cell{Rune: ' ', BG: node.RGB(0x80, 0, 0)})
+	}
+	var d Differ
+	d.Diff(cell.NewBuffer(80, 1), a)
+	if got, want := len(d.Diff(a, b)), len(Diff(a, b)); got != want {
+		t.Fatalf("got %d changes, want %d", got, want)
+	}
+}
+
+func TestDifferResize(t *testing.T) {
+	var d Differ
+	changes := d.Diff(cell.NewBuffer(4, 2), cell.NewBuffer(5, 3))
+	if len(changes) != 3 || len(changes[0].Cells) != 5 {
+		t.Fatalf("expected a full redraw, got %+v", changes)
+	}
+}
+
+func TestDifferReusesSlices(t *testing.T) {
+	var d Differ
+	a := cell.NewBuffer(40, 10)
+	b := cell.NewBuffer(40, 10)
+	b.WriteString(0, 3, "changed", 0, 0, 0)
+	allocs := testing.AllocsPerRun(10, func() {
+		d.Diff(a, b)
+		d.Diff(b, a)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations per frame, got %v", allocs)
+	}
+}
+
+// logFrame draws a header, lines first..first+5 of a log in rows 2-7,
+// and a footer.
+func logFrame(first int) *cell.Buffer {
+	b := cell.NewBuffer(20, 10)
+	b.WriteString(0, 0, "header", 0, 0, 0)
+	for i := range 6 {
+		b.WriteString(0, 2+i, "log line "+string(rune('a'+first+i)), 0, 0, 0)
+	}
+	b.WriteString(0, 9, "footer", 0, 0, 0)
+	return b
+}
+
+// scrolled applies s and changes to a copy of prev, as a terminal would.
+func scrolled(prev *cell.Buffer, s Scroll, changes []Change) *cell.Buffer {
+	b := cell.NewBuffer(prev.Width, prev.Height)
+	copy(b.Cells, prev.Cells)
+	if s.N != 0 {
+		for y := s.Top; y <= s.Bottom; y++ {
+			dst := b.Cells[y*b.Width : (y+1)*b.Width]
+			if src := y + s.N; src >= s.Top && src <= s.Bottom {
+				copy(dst, prev.Cells[src*b.Width:(src+1)*b.Width])
+			} else {
+				copy(dst, cell.NewBuffer(b.Width, 1).Cells)
+			}
+		}
+	}
+	for _, ch := range changes {
+		copy(b.Cells[ch.Y*b.Width+ch.X:], ch.Cells)
+	}
+	return b
+}
+
+func TestDiffScrollLogTail(t *testing.T) {
+	var d Differ
+	prev, next := logFrame(0), logFrame(1)
+	s, changes := d.DiffScroll(prev, next)
+	if s != (Scroll{Top: 2, Bottom: 7, N: 1}) {
+		t.Fatalf("scroll = %+v", s)
+	}
+	if len(changes) != 1 || changes[0].Y != 7 {
+		t.Fatalf("expected only the new line, got %+v", changes)
+	}
+	if !reflect.DeepEqual(scrolled(prev, s, changes).Cells, next.Cells) {
+		t.Fatal("scroll and changes don't reproduce the frame")
+	}
+
+	// Scrolling back moves the block down.
+	s, changes = d.DiffScroll(next, prev)
+	if s != (Scroll{Top: 2, Bottom: 7, N: -1}) || len(changes) != 1 || changes[0].Y != 2 {
+		t.Fatalf("scroll = %+v, changes %+v", s, changes)
+	}
+	if !reflect.DeepEqual(scrolled(next, s, changes).Cells, prev.Cells) {
+		t.Fatal("scroll and changes don't reproduce the frame")
+	}
+}
+
+func TestFindScrollCountsBlankedRows(t *testing.T) {
+	// Shifting rows 0-1 up one fixes both, but blanks row 2, which was
+	// already right: a net gain of one row isn't worth scrolling.
+	prev := []uint64{1, 2, 3, 4, 5, 6, 7, 8}
+	next := []uint64{2, 3, 3, 4, 5, 6, 7, 8}
+	if s := findScroll(prev, next); s.N != 0 {
+		t.Fatalf("scroll = %+v, want none", s)
+	}
+	// The same shift is worth it when row 2 changed anyway.
+	next = []uint64{2, 3, 9, 4, 5, 6, 7, 8}
+	if s := findScroll(prev, next); s != (Scroll{Top: 0, Bottom: 2, N: 1}) {
+		t.Fatalf("scroll = %+v, want rows 0-2 up one", s)
+	}
+	// Scrolling down blanks rows at the top of the block.
+	next = []uint64{1, 1, 2, 4, 5, 6, 7, 8}
+	if s := findScroll(prev, next); s.N != 0 {
+		t.Fatalf("down scroll = %+v, want none", s)
+	}
+}
+
+func TestDiffScrollNeedsEqualRows(t *testing.T) {
+	// Each next row is the prev row below it with the background of
+	// cells 2 and 54 changed. The old row hash took those rows to be
+	// equal, so it scrolled and then skipped them.
+	frame := func(first int, changed bool) *cell.Buffer {
+		b := cell.NewBuffer(80, 8)
+		for y := range b.Height {
+			for x := range b.Width {
+				b.Set(x, y, cell.Cell{Rune: ' ', BG: node.RGB(0, 0, 0)})
+			}
+			b.WriteString(0, y, "log line "+string(rune('a'+first+y)), 0, node.RGB(0, 0, 0), 0)
+			if changed {
+				b.Set(2, y, cell.Cell{Rune: 'g', BG: node.RGB(0x80, 0, 0)})
+				b.Set(54, y, cell.Cell{Rune: ' ', BG: node.RGB(0x80, 0, 0)})
+			}
+		}
+		return b
+	}
+	var d Differ
+	prev, next := frame(0, false), frame(1, true)
+	s, changes := d.DiffScroll(prev, next)
+	if s.N != 0 {
+		t.Fatalf("scroll = %+v, want none", s)
+	}
+	if !reflect.DeepEqual(scrolled(prev, s, changes).Cells, next.Cells) {
+		t.Fatal("changes don't reproduce the frame")
+	}
+}
+
+func TestDiffScrollNotWorthIt(t *testing.T) {
+	var d Differ
+	prev := logFrame(0)
+	next := logFrame(0)
+	next.WriteString(0, 3, "edited", 0, 0, 0)
+	if s, changes := d.DiffScroll(prev, next); s.N != 0 || len(changes) != 1 {
+		t.Fatalf("scroll = %+v, changes %+v", s, changes)
+	}
+}
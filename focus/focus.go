@@ -154,6 +154,9 @@ func (m *Manager) FocusableCount() int {
 }
 
 func collectFocusables(ln layout.LayoutNode, keys *[]string) {
+	if ln.Node.Props.PassThrough {
+		return
+	}
 	if ln.Node.Props.Focusable && ln.Node.Props.Key != "" {
 		*keys = append(*keys, ln.Node.Props.Key)
 	}
@@ -48,6 +48,19 @@ const (
 	AltUp
 	AltDown
 	Paste // Bracketed paste — Key.Rune is unused; full text is in Key.Text
+	CtrlK
+	CtrlR
+	CtrlU
+	CtrlW
+	CtrlY
+	AltBackspace
+	ShiftLeft
+	ShiftRight
+	ShiftUp
+	ShiftDown
+	ShiftHome
+	ShiftEnd
+	MouseDrag // motion with a button held (needs button-event tracking)
 )
 
 // Key represents a keyboard input event.
@@ -158,8 +171,9 @@ func ReadKeys(ctx context.Context, r io.Reader) <-chan Key {
 							send(ch, ctx, Key{Type: Escape})
 							return
 						}
-						// Got follow-up data — check if it continues an escape sequence
-						if rr2.data[0] == '[' {
+						// Got follow-up data — check if it continues an escape
+						// sequence (or is Alt+Backspace split across reads)
+						if rr2.data[0] == '[' || rr2.data[0] == 0x7f {
 							// Combine ESC + new data as a single escape sequence
 							combined := make([]byte, 1+len(rr2.data))
 							combined[0] = 0x1b
@@ -318,6 +332,12 @@ func parseInput(data []byte) []Key {
 					continue
 				}
 			}
+			// Alt+Backspace (ESC followed by DEL or BS)
+			if i+1 < len(data) && (data[i+1] == 0x7f || data[i+1] == '\b') {
+				keys = append(keys, Key{Type: AltBackspace})
+				i += 2
+				continue
+			}
 			// Alt+Enter (ESC followed by CR or LF) → ShiftEnter
 			if i+1 < len(data) && (data[i+1] == '\r' || data[i+1] == '\n') {
 				keys = append(keys, Key{Type: ShiftEnter})
@@ -348,6 +368,21 @@ func parseInput(data []byte) []Key {
 		} else if data[i] == 0x1a { // Ctrl+Z
 			keys = append(keys, Key{Type: CtrlZ})
 			i++
+		} else if data[i] == 0x0b { // Ctrl+K
+			keys = append(keys, Key{Type: CtrlK})
+			i++
+		} else if data[i] == 0x12 { // Ctrl+R
+			keys = append(keys, Key{Type: CtrlR})
+			i++
+		} else if data[i] == 0x15 { // Ctrl+U
+			keys = append(keys, Key{Type: CtrlU})
+			i++
+		} else if data[i] == 0x17 { // Ctrl+W
+			keys = append(keys, Key{Type: CtrlW})
+			i++
+		} else if data[i] == 0x19 { // Ctrl+Y
+			keys = append(keys, Key{Type: CtrlY})
+			i++
 		} else if data[i] >= 0x20 { // printable or multi-byte UTF-8
 			r, size := decodeRune(data[i:])
 			keys = append(keys, Key{Type: RuneKey, Rune: r})
@@ -396,6 +431,23 @@ func parseCSI(data []byte) (Key, int) {
 			return Key{Type: AltLeft}, 4
 		}
 	}
+	// Shift modifier: \x1b[1;2D (Shift+Left) etc.
+	if len(data) >= 4 && data[0] == '1' && data[1] == ';' && data[2] == '2' {
+		switch data[3] {
+		case 'A':
+			return Key{Type: ShiftUp}, 4
+		case 'B':
+			return Key{Type: ShiftDown}, 4
+		case 'C':
+			return Key{Type: ShiftRight}, 4
+		case 'D':
+			return Key{Type: ShiftLeft}, 4
+		case 'H':
+			return Key{Type: ShiftHome}, 4
+		case 'F':
+			return Key{Type: ShiftEnd}, 4
+		}
+	}
 	// Handle sequences like \x1b[5~ (PageUp), \x1b[6~ (PageDown), \x1b[3~ (Delete)
 	if len(data) >= 2 && data[1] == '~' {
 		switch data[0] {
@@ -426,6 +478,8 @@ func parseCSI(data []byte) (Key, int) {
 					kt = MouseScrollDown
 				case data[j] == 'm':
 					kt = MouseRelease
+				case btn&32 != 0:
+					kt = MouseDrag
 				}
 				return Key{Type: kt, MouseX: x - 1, MouseY: y - 1}, j + 1
 			}
@@ -435,13 +489,15 @@ func parseCSI(data []byte) (Key, int) {
 	if len(data) >= 1 && data[0] == 'M' && len(data) >= 4 {
 		btn := data[1] - 32
 		kt := MouseClick
-		switch btn {
-		case 3:
+		switch {
+		case btn == 3:
 			kt = MouseRelease
-		case 64:
+		case btn == 64:
 			kt = MouseScrollUp
-		case 65:
+		case btn == 65:
 			kt = MouseScrollDown
+		case btn&32 != 0:
+			kt = MouseDrag
 		}
 		return Key{Type: kt, MouseX: int(data[2]) - 33, MouseY: int(data[3]) - 33}, 4
 	}
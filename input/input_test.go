@@ -186,3 +186,51 @@ func TestBracketedPasteEmpty(t *testing.T) {
 		t.Errorf("expected empty Paste, got %v", keys[0])
 	}
 }
+
+func TestParseEditingKeys(t *testing.T) {
+	tests := []struct {
+		input    []byte
+		expected KeyType
+	}{
+		{[]byte{0x17}, CtrlW},
+		{[]byte{0x15}, CtrlU},
+		{[]byte{0x0b}, CtrlK},
+		{[]byte{0x19}, CtrlY},
+		{[]byte{0x12}, CtrlR},
+		{[]byte{0x1b, 0x7f}, AltBackspace},
+		{[]byte("\x1b[1;2D"), ShiftLeft},
+		{[]byte("\x1b[1;2C"), ShiftRight},
+		{[]byte("\x1b[1;2A"), ShiftUp},
+		{[]byte("\x1b[1;2B"), ShiftDown},
+		{[]byte("\x1b[1;2H"), ShiftHome},
+		{[]byte("\x1b[1;2F"), ShiftEnd},
+	}
+	for _, tt := range tests {
+		keys := parseInput(tt.input)
+		if len(keys) != 1 || keys[0].Type != tt.expected {
+			t.Errorf("input %q: expected %d, got %v", tt.input, tt.expected, keys)
+		}
+	}
+}
+
+func TestParseMouseDrag(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected KeyType
+	}{
+		{"\x1b[<0;5;3M", MouseClick},
+		{"\x1b[<32;6;3M", MouseDrag},
+		{"\x1b[<0;6;3m", MouseRelease},
+		{"\x1b[M@&#", MouseDrag}, // legacy encoding: btn 32
+	}
+	for _, tt := range tests {
+		keys := parseInput([]byte(tt.input))
+		if len(keys) != 1 || keys[0].Type != tt.expected {
+			t.Errorf("input %q: expected %d, got %v", tt.input, tt.expected, keys)
+		}
+	}
+	keys := parseInput([]byte("\x1b[<32;6;3M"))
+	if keys[0].MouseX != 5 || keys[0].MouseY != 2 {
+		t.Errorf("drag position = %d,%d, want 5,2", keys[0].MouseX, keys[0].MouseY)
+	}
+}
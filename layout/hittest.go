@@ -4,7 +4,8 @@ package layout
 // from the root down to the deepest match. At each level the topmost
 // child wins — children later in the list paint over earlier ones, so
 // they are checked in reverse order. Returns nil if the point is
-// outside the tree.
+// outside the tree. Subtrees marked PassThrough are skipped, so a point
+// under one hits the layer beneath it.
 func HitTest(root LayoutNode, x, y int) []LayoutNode {
 	if !contains(root.Rect, x, y) {
 		return nil
@@ -14,7 +15,7 @@ func HitTest(root LayoutNode, x, y int) []LayoutNode {
 	for {
 		var next *LayoutNode
 		for i := len(cur.Children) - 1; i >= 0; i-- {
-			if contains(cur.Children[i].Rect, x, y) {
+			if c := &cur.Children[i]; !c.Node.Props.PassThrough && contains(c.Rect, x, y) {
 				next = &cur.Children[i]
 				break
 			}
@@ -57,3 +57,15 @@ func TestHitTestPrefersTopmostOverlayLayer(t *testing.T) {
 		t.Fatalf("topmost layer should win, got %q", deepest.Node.Props.Key)
 	}
 }
+
+func TestHitTestSkipsPassThrough(t *testing.T) {
+	root := node.Overlay(
+		node.Text("base").WithKey("base"),
+		node.Column(node.Text("toast").WithKey("toast")).WithPassThrough(),
+	)
+	lt := Layout(root, 10, 2)
+	path := HitTest(lt, 0, 0)
+	if got := path[len(path)-1].Node.Props.Key; got != "base" {
+		t.Fatalf("expected click to reach base, got %q", got)
+	}
+}
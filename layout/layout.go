@@ -19,6 +19,10 @@ type LayoutNode struct {
 	// Lines holds the wrapped text lines for TextNodes, computed once
 	// during layout so paint doesn't re-wrap.
 	Lines []string
+
+	// shared marks a subtree also held by a Cache, which must be copied
+	// rather than moved in place.
+	shared bool
 }
 
 // textLines returns the render lines for a text node at the given
@@ -40,24 +44,105 @@ func borderInset(n node.Node) int {
 
 // Layout computes positions for the node tree within the given terminal size.
 func Layout(root node.Node, termW, termH int) LayoutNode {
-	return layout(root, Rect{0, 0, termW, termH})
+	var c *Cache
+	return c.layout(root, Rect{0, 0, termW, termH})
+}
+
+// Cache memoizes layout across frames for subtrees marked with
+// node.WithMemo. A memoized subtree given the same size as in the last
+// frame reuses that frame's layout, moved if its position changed, and
+// its measured size is reused too. Entries unused for a frame are
+// dropped. The zero value is ready to use; a nil Cache caches nothing.
+type Cache struct {
+	prev, cur         map[string]memoLayout
+	prevSize, curSize map[sizeKey]int
+}
+
+type memoLayout struct {
+	avail Rect
+	ln    LayoutNode
+}
+
+// sizeKey identifies a memoized measurement: a subtree's width or
+// height for an available width.
+type sizeKey struct {
+	memo   string
+	w      int
+	height bool
+}
+
+// Layout is like the package-level Layout, reusing memoized subtrees
+// from the previous call.
+func (c *Cache) Layout(root node.Node, termW, termH int) LayoutNode {
+	c.prev, c.cur = c.cur, c.prev
+	c.prevSize, c.curSize = c.curSize, c.prevSize
+	if c.cur == nil {
+		c.cur = make(map[string]memoLayout)
+		c.curSize = make(map[sizeKey]int)
+	}
+	clear(c.cur)
+	clear(c.curSize)
+	return c.layout(root, Rect{0, 0, termW, termH})
 }
 
-func layout(n node.Node, avail Rect) LayoutNode {
+func (c *Cache) layout(n node.Node, avail Rect) LayoutNode {
+	key := n.Props.Memo
+	if c == nil || key == "" {
+		return c.layoutNode(n, avail)
+	}
+	m, ok := c.cur[key]
+	if !ok {
+		m, ok = c.prev[key]
+	}
+	switch {
+	case ok && m.avail == avail:
+	case ok && m.avail.W == avail.W && m.avail.H == avail.H:
+		m = memoLayout{avail, translate(m.ln, avail.X-m.avail.X, avail.Y-m.avail.Y)}
+	default:
+		m = memoLayout{avail, c.layoutNode(n, avail)}
+		markShared(&m.ln)
+	}
+	c.cur[key] = m
+	return m.ln
+}
+
+// size returns a memoized measurement, if there is one.
+func (c *Cache) size(k sizeKey) (int, bool) {
+	if c == nil || k.memo == "" {
+		return 0, false
+	}
+	v, ok := c.curSize[k]
+	if !ok {
+		if v, ok = c.prevSize[k]; ok {
+			c.curSize[k] = v
+		}
+	}
+	return v, ok
+}
+
+// setSize memoizes a measurement for a subtree with a Memo key.
+func (c *Cache) setSize(k sizeKey, v int) int {
+	if c != nil && k.memo != "" {
+		c.curSize[k] = v
+	}
+	return v
+}
+
+func (c *Cache) layoutNode(n node.Node, avail Rect) LayoutNode {
 	ln := LayoutNode{Node: n, Rect: avail}
 
 	switch n.Type {
 	case node.TextNode:
 		ln = layoutText(n, avail)
 	case node.RowNode:
-		ln = layoutRow(n, avail)
+		ln = c.layoutRow(n, avail)
 	case node.ColumnNode, node.ListNode, node.PaneNode:
-		ln = layoutColumn(n, avail)
+		ln = c.layoutColumn(n, avail)
 	case node.BoxNode:
-		ln = layoutBox(n, avail)
+		ln = c.layoutBox(n, avail)
 	case node.OverlayNode:
-		ln = layoutOverlay(n, avail)
-	case node.SpacerNode:
+		ln = c.layoutOverlay(n, avail)
+	case node.SpacerNode, node.CanvasNode, node.RuleNode:
 		ln.Rect = avail
 	}
 
@@ -93,7 +178,7 @@ func layoutText(n node.Node, avail Rect) LayoutNode {
 	}
 }
 
-func layoutRow(n node.Node, avail Rect) LayoutNode {
+func (c *Cache) layoutRow(n node.Node, avail Rect) LayoutNode {
 	ln := LayoutNode{Node: n, Rect: avail}
 	if len(n.Children) == 0 {
 		return ln
@@ -108,7 +193,7 @@ func layoutRow(n node.Node, avail Rect) LayoutNode {
 		if fw > 0 {
 			totalFlex += fw
 		} else {
-			totalFixed += measureWidth(child, inner)
+			totalFixed += c.measureWidth(child, inner)
 		}
 	}
 
@@ -125,7 +210,7 @@ func layoutRow(n node.Node, avail Rect) LayoutNode {
 		if fw > 0 && totalFlex > 0 {
 			childW = (remaining * fw) / totalFlex
 		} else {
-			childW = measureWidth(child, inner)
+			childW = c.measureWidth(child, inner)
 		}
 		if childW > inner.W-(x-inner.X) {
 			childW = inner.W - (x - inner.X)
@@ -134,14 +219,14 @@ func layoutRow(n node.Node, avail Rect) LayoutNode {
 			childW = 0
 		}
 		childRect := Rect{x, inner.Y, childW, inner.H}
-		ln.Children = append(ln.Children, layout(child, childRect))
+		ln.Children = append(ln.Children, c.layout(child, childRect))
 		x += childW
 	}
 
 	return ln
 }
 
-func layoutColumn(n node.Node, avail Rect) LayoutNode {
+func (c *Cache) layoutColumn(n node.Node, avail Rect) LayoutNode {
 	ln := LayoutNode{Node: n, Rect: avail}
 	if len(n.Children) == 0 {
 		return ln
@@ -158,7 +243,7 @@ func layoutColumn(n node.Node, avail Rect) LayoutNode {
 		if fw > 0 {
 			totalFlex += fw
 		} else {
-			totalFixed += measureHeight(child, inner)
+			totalFixed += c.measureHeight(child, inner)
 		}
 	}
 
@@ -175,7 +260,7 @@ func layoutColumn(n node.Node, avail Rect) LayoutNode {
 		if fw > 0 && totalFlex > 0 {
 			childH = (remaining * fw) / totalFlex
 		} else {
-			childH = measureHeight(child, inner)
+			childH = c.measureHeight(child, inner)
 		}
 		if !scrollable {
 			if childH > inner.H-(y-inner.Y) {
@@ -186,7 +271,7 @@ func layoutColumn(n node.Node, avail Rect) LayoutNode {
 			}
 		}
 		childRect := Rect{inner.X, y, inner.W, childH}
-		ln.Children = append(ln.Children, layout(child, childRect))
+		ln.Children = append(ln.Children, c.layout(child, childRect))
 		y += childH
 	}
 
@@ -214,7 +299,7 @@ func layoutColumn(n node.Node, avail Rect) LayoutNode {
 	return ln
 }
 
-func layoutBox(n node.Node, avail Rect) LayoutNode {
+func (c *Cache) layoutBox(n node.Node, avail Rect) LayoutNode {
 	ln := LayoutNode{Node: n, Rect: avail}
 	if len(n.Children) == 0 {
 		return ln
@@ -234,17 +319,17 @@ func layoutBox(n node.Node, avail Rect) LayoutNode {
 	if innerRect.H < 0 {
 		innerRect.H = 0
 	}
-	ln.Children = append(ln.Children, layout(n.Children[0], innerRect))
+	ln.Children = append(ln.Children, c.layout(n.Children[0], innerRect))
 	return ln
 }
 
 // layoutOverlay stacks every child in the full available rect. Children
 // are painted in order, so later children appear on top.
-func layoutOverlay(n node.Node, avail Rect) LayoutNode {
+func (c *Cache) layoutOverlay(n node.Node, avail Rect) LayoutNode {
 	ln := LayoutNode{Node: n, Rect: avail}
 	inner := insetPadding(avail, n)
 	for _, child := range n.Children {
-		ln.Children = append(ln.Children, layout(child, inner))
+		ln.Children = append(ln.Children, c.layout(child, inner))
 	}
 	return ln
 }
@@ -269,6 +354,19 @@ func insetPadding(r Rect, n node.Node) Rect {
 
 // measureWidth returns the intrinsic width of a non-flex node.
 func measureWidth(n node.Node, avail Rect) int {
+	var c *Cache
+	return c.measureWidth(n, avail)
+}
+
+func (c *Cache) measureWidth(n node.Node, avail Rect) int {
+	k := sizeKey{n.Props.Memo, avail.W, false}
+	if v, ok := c.size(k); ok {
+		return v
+	}
+	return c.setSize(k, c.measureNodeWidth(n, avail))
+}
+
+func (c *Cache) measureNodeWidth(n node.Node, avail Rect) int {
 	if n.Props.Width > 0 {
 		return n.Props.Width
 	}
@@ -285,20 +383,20 @@ func measureWidth(n node.Node, avail Rect) int {
 	case node.BoxNode:
 		b := 2 * borderInset(n)
 		if len(n.Children) > 0 {
-			return measureWidth(n.Children[0], avail) + b + pl + pr
+			return c.measureWidth(n.Children[0], avail) + b + pl + pr
 		}
 		return b + pl + pr
 	case node.RowNode:
 		w := 0
-		for _, c := range n.Children {
-			w += measureWidth(c, avail)
+		for _, child := range n.Children {
+			w += c.measureWidth(child, avail)
 		}
 		return w + pl + pr
 	case node.OverlayNode:
 		// Wide enough for the widest layer.
 		w := 0
-		for _, c := range n.Children {
-			if cw := measureWidth(c, avail); cw > w {
+		for _, child := range n.Children {
+			if cw := c.measureWidth(child, avail); cw > w {
 				w = cw
 			}
 		}
@@ -313,6 +411,19 @@ func measureWidth(n node.Node, avail Rect) int {
 
 // measureHeight returns the intrinsic height of a non-flex node.
 func measureHeight(n node.Node, avail Rect) int {
+	var c *Cache
+	return c.measureHeight(n, avail)
+}
+
+func (c *Cache) measureHeight(n node.Node, avail Rect) int {
+	k := sizeKey{n.Props.Memo, avail.W, true}
+	if v, ok := c.size(k); ok {
+		return v
+	}
+	return c.setSize(k, c.measureNodeHeight(n, avail))
+}
+
+func (c *Cache) measureNodeHeight(n node.Node, avail Rect) int {
 	if n.Props.Height > 0 {
 		return n.Props.Height
 	}
@@ -332,19 +443,19 @@ func measureHeight(n node.Node, avail Rect) int {
 			if innerAvail.W < 0 {
 				innerAvail.W = 0
 			}
-			return measureHeight(n.Children[0], innerAvail) + b + pt + pb
+			return c.measureHeight(n.Children[0], innerAvail) + b + pt + pb
 		}
 		return b + pt + pb
 	case node.ColumnNode, node.ListNode, node.PaneNode:
 		h := 0
-		for _, c := range n.Children {
-			h += measureHeight(c, avail)
+		for _, child := range n.Children {
+			h += c.measureHeight(child, avail)
 		}
 		return h + pt + pb
 	case node.RowNode:
 		h := 1
-		for _, c := range n.Children {
-			ch := measureHeight(c, avail)
+		for _, child := range n.Children {
+			ch := c.measureHeight(child, avail)
 			if ch > h {
 				h = ch
 			}
@@ -353,8 +464,8 @@ func measureHeight(n node.Node, avail Rect) int {
 	case node.OverlayNode:
 		// Tall enough for the tallest layer.
 		h := 0
-		for _, c := range n.Children {
-			if ch := measureHeight(c, avail); ch > h {
+		for _, child := range n.Children {
+			if ch := c.measureHeight(child, avail); ch > h {
 				h = ch
 			}
 		}
@@ -369,12 +480,39 @@ func measureHeight(n node.Node, avail Rect) int {
 
 // shiftY recursively shifts a layout node and all descendants by dy.
 func shiftY(ln *LayoutNode, dy int) {
+	if ln.shared {
+		*ln = translate(*ln, 0, dy)
+		return
+	}
 	ln.Rect.Y += dy
 	for i := range ln.Children {
 		shiftY(&ln.Children[i], dy)
 	}
 }
 
+// translate returns a copy of a shared subtree moved by (dx, dy).
+func translate(ln LayoutNode, dx, dy int) LayoutNode {
+	ln.Rect.X += dx
+	ln.Rect.Y += dy
+	ln.shared = true
+	if len(ln.Children) > 0 {
+		children := make([]LayoutNode, len(ln.Children))
+		for i, ch := range ln.Children {
+			children[i] = translate(ch, dx, dy)
+		}
+		ln.Children = children
+	}
+	return ln
+}
+
+// markShared marks a subtree as held by a Cache.
+func markShared(ln *LayoutNode) {
+	ln.shared = true
+	for i := range ln.Children {
+		markShared(&ln.Children[i])
+	}
+}
+
 func flexWeight(n node.Node) int {
 	return n.Props.FlexWeight
 }
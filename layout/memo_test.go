@@ -0,0 +1,84 @@
+package layout
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stukennedy/tooey/node"
+)
+
+// dump flattens a layout tree into one line per node with its rect and
+// lines, for comparing trees.
+func dump(ln LayoutNode) string {
+	var b strings.Builder
+	var walk func(ln LayoutNode, depth int)
+	walk = func(ln LayoutNode, depth int) {
+		fmt.Fprintf(&b, "%*s%v %q %q\n", depth*2, "", ln.Rect, ln.Node.Props.Text, ln.Lines)
+		for _, c := range ln.Children {
+			walk(c, depth+1)
+		}
+	}
+	walk(ln, 0)
+	return b.String()
+}
+
+// chat lays out a scrolled-to-bottom log of memoized messages under a
+// header that says how many there are.
+func chat(n int) node.Node {
+	var msgs []node.Node
+	for i := range n {
+		text := fmt.Sprintf("message %d is long enough to wrap onto a second line", i)
+		msgs = append(msgs, node.Box(node.BorderSingle, node.Text(text)).WithMemo(fmt.Sprint("msg", i)))
+	}
+	return node.Column(
+		node.Text(fmt.Sprintf("%d messages", n)),
+		node.Column(msgs...).WithFlex(1).WithScrollToBottom(),
+	)
+}
+
+func TestCacheMatchesLayout(t *testing.T) {
+	var c Cache
+	for n := 1; n < 8; n++ {
+		for range 2 { // once fresh, once from the cache
+			if got, want := dump(c.Layout(chat(n), 30, 12)), dump(Layout(chat(n), 30, 12)); got != want {
+				t.Fatalf("%d messages:\n%s\nwant\n%s", n, got, want)
+			}
+		}
+	}
+	// A new width invalidates the sizes.
+	if got, want := dump(c.Layout(chat(7), 24, 12)), dump(Layout(chat(7), 24, 12)); got != want {
+		t.Fatalf("after resize:\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCacheReusesMemoizedSubtree(t *testing.T) {
+	var c Cache
+	c.Layout(node.Column(node.Text("old").WithMemo("k")), 10, 5)
+
+	// Same key and size: the old layout comes back, which is why the
+	// key must change with the content.
+	lt := c.Layout(node.Column(node.Text("new").WithMemo("k")), 10, 5)
+	if got := lt.Children[0].Lines; len(got) != 1 || got[0] != "old" {
+		t.Fatalf("expected the memoized lines, got %q", got)
+	}
+	lt = c.Layout(node.Column(node.Text("new").WithMemo("k2")), 10, 5)
+	if got := lt.Children[0].Lines; got[0] != "new" {
+		t.Fatalf("new key should lay out again, got %q", got)
+	}
+}
+
+func TestCacheMovesMemoizedSubtree(t *testing.T) {
+	var c Cache
+	memo := node.Text("x").WithMemo("k")
+	c.Layout(node.Column(memo), 10, 5)
+	lt := c.Layout(node.Column(node.Text("above"), memo), 10, 5)
+	if r := lt.Children[1].Rect; r != (Rect{0, 1, 10, 1}) {
+		t.Fatalf("moved subtree at %+v", r)
+	}
+	// The cached copy is untouched by the move.
+	lt = c.Layout(node.Column(memo), 10, 5)
+	if r := lt.Children[0].Rect; r != (Rect{0, 0, 10, 1}) {
+		t.Fatalf("subtree back at %+v", r)
+	}
+}
@@ -0,0 +1,489 @@
+package markdown
+
+import "strings"
+
+// blockKind identifies a parsed markdown block.
+type blockKind int
+
+const (
+	blankBlock blockKind = iota
+	paragraphBlock
+	headingBlock
+	ruleBlock
+	codeBlock
+	quoteBlock
+	listBlock
+	tableBlock
+)
+
+// block is one node of the parsed document. Container blocks (quotes
+// and list items) hold child blocks; leaf blocks hold their text.
+type block struct {
+	kind blockKind
+
+	// text is the inline content of paragraphs and headings; hard line
+	// breaks are kept as "\n".
+	text  string
+	level int // heading level, 1-6
+
+	lines []string // code lines
+	lang  string   // fenced code info string language
+
+	children []block // blockquote content
+
+	// Lists.
+	ordered bool
+	start   int // first number of an ordered list
+	items   []listItem
+
+	// Tables.
+	header []string
+	align  []cellAlign
+	rows   [][]string
+}
+
+// listItem is one item of a list block.
+type listItem struct {
+	blocks []block
+	task   int  // -1 not a task, 0 unchecked, 1 checked
+	loose  bool // separated from the next item by a blank line
+}
+
+// cellAlign is a table column's alignment.
+type cellAlign int
+
+const (
+	alignLeft cellAlign = iota
+	alignCenter
+	alignRight
+)
+
+// splitLines splits text into lines, dropping carriage returns and
+// expanding tabs in indentation to four-column stops.
+func splitLines(text string) []string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, l := range lines {
+		if !strings.HasPrefix(strings.TrimLeft(l, " "), "\t") {
+			continue
+		}
+		col, j := 0, 0
+		for ; j < len(l) && (l[j] == ' ' || l[j] == '\t'); j++ {
+			if l[j] == '\t' {
+				col += 4 - col%4
+			} else {
+				col++
+			}
+		}
+		lines[i] = strings.Repeat(" ", col) + l[j:]
+	}
+	return lines
+}
+
+// parseBlocks parses lines into a sequence of blocks.
+func parseBlocks(lines []string) []block {
+	var blocks []block
+	for i := 0; i < len(lines); {
+		b, next := parseBlock(lines, i)
+		blocks = append(blocks, b)
+		i = next
+	}
+	return blocks
+}
+
+// parseBlock parses the block starting at lines[i] and returns it with
+// the index of the line after it.
+func parseBlock(lines []string, i int) (block, int) {
+	line := lines[i]
+	switch {
+	case isBlank(line):
+		return block{kind: blankBlock}, i + 1
+	case isFence(line):
+		return parseFence(lines, i)
+	case indentOf(line) >= 4:
+		return parseIndentedCode(lines, i)
+	}
+	if level, content := parseHeading(line); level > 0 {
+		return block{kind: headingBlock, level: level, text: content}, i + 1
+	}
+	if isHorizontalRule(strings.TrimSpace(line)) {
+		return block{kind: ruleBlock}, i + 1
+	}
+	if isQuote(line) {
+		return parseQuote(lines, i)
+	}
+	if m, _, ok := parseListMarker(line); ok {
+		return parseList(lines, i, m)
+	}
+	if b, next, ok := parseTable(lines, i); ok {
+		return b, next
+	}
+	return parseParagraph(lines, i)
+}
+
+func isBlank(line string) bool { return strings.TrimSpace(line) == "" }
+
+// indentOf returns the number of leading spaces.
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// startsBlock reports whether line begins a block that interrupts a
+// paragraph.
+func startsBlock(line string) bool {
+	if indentOf(line) >= 4 {
+		return false
+	}
+	if isFence(line) || isQuote(line) || isHorizontalRule(strings.TrimSpace(line)) {
+		return true
+	}
+	if level, _ := parseHeading(line); level > 0 {
+		return true
+	}
+	// Only non-empty lists, and ordered lists starting at 1, interrupt
+	// a paragraph; "2019. was a year" stays prose.
+	if m, rest, ok := parseListMarker(line); ok && strings.TrimSpace(rest) != "" {
+		return !m.ordered || m.start == 1
+	}
+	return false
+}
+
+func isFence(line string) bool {
+	t := strings.TrimLeft(line, " ")
+	return indentOf(line) < 4 && (strings.HasPrefix(t, "```") || strings.HasPrefix(t, "~~~"))
+}
+
+func isQuote(line string) bool {
+	return indentOf(line) < 4 && strings.HasPrefix(strings.TrimLeft(line, " "), ">")
+}
+
+// parseFence parses a fenced code block. The block runs to a closing
+// fence of the same character at least as long as the opening one, or
+// to the end of the input.
+func parseFence(lines []string, i int) (block, int) {
+	open := strings.TrimLeft(lines[i], " ")
+	indent := indentOf(lines[i])
+	n := len(open) - len(strings.TrimLeft(open, open[:1]))
+	fence := open[:n]
+	b := block{kind: codeBlock, lang: fenceLang(open)}
+	j := i + 1
+	for ; j < len(lines); j++ {
+		t := strings.TrimSpace(lines[j])
+		if indentOf(lines[j]) < 4 && strings.HasPrefix(t, fence) && strings.Trim(t, fence[:1]) == "" {
+			j++
+			break
+		}
+		// Content is de-indented by the opening fence's indent.
+		l := lines[j]
+		l = l[min(indent, indentOf(l)):]
+		b.lines = append(b.lines, l)
+	}
+	return b, j
+}
+
+// parseIndentedCode parses a block of lines indented four or more
+// spaces; blank lines inside it are kept, trailing ones are not.
+func parseIndentedCode(lines []string, i int) (block, int) {
+	b := block{kind: codeBlock}
+	j := i
+	end := i
+	for ; j < len(lines); j++ {
+		l := lines[j]
+		if isBlank(l) {
+			b.lines = append(b.lines, "")
+			continue
+		}
+		if indentOf(l) < 4 {
+			break
+		}
+		b.lines = append(b.lines, l[4:])
+		end = j + 1
+	}
+	b.lines = b.lines[:end-i]
+	return b, end
+}
+
+// parseQuote parses a blockquote: lines starting with '>' plus lazy
+// paragraph continuation lines.
+func parseQuote(lines []string, i int) (block, int) {
+	var inner []string
+	j := i
+	for ; j < len(lines); j++ {
+		l := lines[j]
+		if isQuote(l) {
+			s := strings.TrimLeft(l, " ")[1:]
+			s = strings.TrimPrefix(s, " ")
+			inner = append(inner, s)
+			continue
+		}
+		if isBlank(l) || startsBlock(l) || len(inner) == 0 || isBlank(inner[len(inner)-1]) {
+			break
+		}
+		inner = append(inner, l)
+	}
+	return block{kind: quoteBlock, children: parseBlocks(inner)}, j
+}
+
+// listMarker describes the marker that opens a list item.
+type listMarker struct {
+	ordered bool
+	delim   byte // '-', '*' or '+' for bullets; '.' or ')' when ordered
+	start   int  // the item's number when ordered
+	content int  // column where the item's content starts
+}
+
+// parseListMarker recognizes a list item marker with at most three
+// spaces of indent and returns it with the rest of the line.
+func parseListMarker(line string) (listMarker, string, bool) {
+	indent := indentOf(line)
+	if indent >= 4 {
+		return listMarker{}, "", false
+	}
+	t := line[indent:]
+	var m listMarker
+	n := 0
+	switch {
+	case t != "" && strings.IndexByte("-*+", t[0]) >= 0:
+		m.delim, n = t[0], 1
+	default:
+		for n < len(t) && n < 9 && t[n] >= '0' && t[n] <= '9' {
+			m.start = m.start*10 + int(t[n]-'0')
+			n++
+		}
+		if n == 0 || n >= len(t) || (t[n] != '.' && t[n] != ')') {
+			return listMarker{}, "", false
+		}
+		m.ordered, m.delim = true, t[n]
+		n++
+	}
+	rest := t[n:]
+	if rest != "" && rest[0] != ' ' {
+		return listMarker{}, "", false
+	}
+	// One to four spaces separate the marker from the content. An
+	// empty item, or one followed by more spaces (indented code in
+	// CommonMark, which we don't distinguish), counts as one.
+	spaces := indentOf(rest)
+	if spaces == len(rest) {
+		m.content = indent + n + 1
+		return m, "", true
+	}
+	if spaces > 4 {
+		spaces = 1
+	}
+	m.content = indent + n + spaces
+	return m, rest[spaces:], true
+}
+
+// sameList reports whether marker b continues the list opened by a.
+func sameList(a, b listMarker) bool {
+	return a.ordered == b.ordered && a.delim == b.delim
+}
+
+// parseList parses consecutive items of one list. Each item's lines
+// are de-indented to its content column and parsed recursively, which
+// is what nests sub-lists.
+func parseList(lines []string, i int, first listMarker) (block, int) {
+	b := block{kind: listBlock, ordered: first.ordered, start: first.start}
+	j := i
+	for j < len(lines) {
+		m, rest, ok := parseListMarker(lines[j])
+		if !ok || !sameList(first, m) {
+			break
+		}
+		item := []string{rest}
+		j++
+	itemLines:
+		for ; j < len(lines); j++ {
+			l := lines[j]
+			_, _, marker := parseListMarker(l)
+			switch {
+			case isBlank(l):
+				item = append(item, "")
+			case indentOf(l) >= m.content:
+				item = append(item, l[m.content:])
+			case !isBlank(item[len(item)-1]) && !startsBlock(l) && !marker:
+				// Lazy continuation of the item's paragraph.
+				item = append(item, strings.TrimLeft(l, " "))
+			default:
+				break itemLines
+			}
+		}
+		// Trailing blank lines belong between items, or after the list.
+		trailing := 0
+		for len(item) > 1 && isBlank(item[len(item)-1]) {
+			item = item[:len(item)-1]
+			trailing++
+		}
+		j -= trailing
+
+		li := listItem{task: -1}
+		if t := item[0]; strings.HasPrefix(t, "[ ] ") || strings.HasPrefix(t, "[x] ") || strings.HasPrefix(t, "[X] ") {
+			li.task = 0
+			if t[1] != ' ' {
+				li.task = 1
+			}
+			item[0] = t[4:]
+		}
+		li.blocks = parseBlocks(item)
+		b.items = append(b.items, li)
+
+		// A blank line followed by another item keeps the list going
+		// and makes it loose.
+		if trailing > 0 {
+			if j+trailing < len(lines) {
+				if next, _, ok := parseListMarker(lines[j+trailing]); ok && sameList(first, next) {
+					b.items[len(b.items)-1].loose = true
+					j += trailing
+					continue
+				}
+			}
+			break
+		}
+	}
+	return b, j
+}
+
+// parseParagraph parses a paragraph, which may turn out to be a setext
+// heading when followed by a line of '=' or '-'.
+func parseParagraph(lines []string, i int) (block, int) {
+	text := []string{strings.TrimSpace(lines[i])}
+	hard := []bool{hardBreak(lines[i])}
+	j := i + 1
+	for ; j < len(lines); j++ {
+		l := lines[j]
+		if isBlank(l) {
+			break
+		}
+		if level := setextLevel(l); level > 0 {
+			return block{kind: headingBlock, level: level, text: joinParagraph(text, hard)}, j + 1
+		}
+		if startsBlock(l) {
+			break
+		}
+		text = append(text, strings.TrimSpace(l))
+		hard = append(hard, hardBreak(l))
+	}
+	return block{kind: paragraphBlock, text: joinParagraph(text, hard)}, j
+}
+
+// setextLevel returns 1 or 2 when line underlines a setext heading.
+func setextLevel(line string) int {
+	t := strings.TrimSpace(line)
+	if indentOf(line) >= 4 || t == "" {
+		return 0
+	}
+	switch {
+	case strings.Trim(t, "=") == "":
+		return 1
+	case strings.Trim(t, "-") == "":
+		return 2
+	}
+	return 0
+}
+
+// hardBreak reports whether a paragraph line ends in a hard line break:
+// two trailing spaces or a backslash.
+func hardBreak(line string) bool {
+	return strings.HasSuffix(line, "  ") || strings.HasSuffix(line, "\\")
+}
+
+// joinParagraph joins paragraph lines with spaces, keeping hard breaks
+// as "\n".
+func joinParagraph(text []string, hard []bool) string {
+	var b strings.Builder
+	for i, t := range text {
+		if i > 0 {
+			if hard[i-1] {
+				b.WriteByte('\n')
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+		if i < len(text)-1 && hard[i] {
+			t = strings.TrimSuffix(t, "\\")
+		}
+		b.WriteString(t)
+	}
+	return b.String()
+}
+
+// parseTable parses a GFM table: a header row, a delimiter row with
+// the same number of cells, and body rows up to a blank line or the
+// start of another block.
+func parseTable(lines []string, i int) (block, int, bool) {
+	if i+1 >= len(lines) || !strings.Contains(lines[i], "|") {
+		return block{}, i, false
+	}
+	header := splitRow(lines[i])
+	align, ok := parseDelimiterRow(lines[i+1])
+	if !ok || len(align) != len(header) {
+		return block{}, i, false
+	}
+	b := block{kind: tableBlock, header: header, align: align}
+	j := i + 2
+	for ; j < len(lines); j++ {
+		l := lines[j]
+		if isBlank(l) || startsBlock(l) {
+			break
+		}
+		row := splitRow(l)
+		// Short rows are padded and long ones cut to the header.
+		row = append(row, make([]string, max(len(header)-len(row), 0))...)
+		b.rows = append(b.rows, row[:len(header)])
+	}
+	return b, j, true
+}
+
+// splitRow splits a table row into trimmed cells on unescaped pipes,
+// ignoring optional leading and trailing pipes.
+func splitRow(line string) []string {
+	t := strings.TrimSpace(line)
+	t = strings.TrimPrefix(t, "|")
+	if strings.HasSuffix(t, "|") && !strings.HasSuffix(t, "\\|") {
+		t = t[:len(t)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	for k := 0; k < len(t); k++ {
+		switch {
+		case t[k] == '\\' && k+1 < len(t) && t[k+1] == '|':
+			cell.WriteByte('|')
+			k++
+		case t[k] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(t[k])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// parseDelimiterRow parses a table delimiter row such as
+// "| :--- | :-: | ---: |" into column alignments.
+func parseDelimiterRow(line string) ([]cellAlign, bool) {
+	if !strings.ContainsAny(line, "-") {
+		return nil, false
+	}
+	cells := splitRow(line)
+	align := make([]cellAlign, len(cells))
+	for k, c := range cells {
+		left, right := strings.HasPrefix(c, ":"), strings.HasSuffix(c, ":")
+		dashes := strings.Trim(c, ":")
+		if dashes == "" || strings.Trim(dashes, "-") != "" {
+			return nil, false
+		}
+		switch {
+		case left && right:
+			align[k] = alignCenter
+		case right:
+			align[k] = alignRight
+		}
+	}
+	// A single-column delimiter must have a pipe, or "---" would be
+	// taken for a table rather than a setext heading or rule.
+	if len(cells) == 1 && !strings.Contains(line, "|") {
+		return nil, false
+	}
+	return align, true
+}
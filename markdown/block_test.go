@@ -0,0 +1,163 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/tooeytest"
+)
+
+// frame renders markdown at width and returns the painted text.
+func frame(src string, width, height int) string {
+	return tooeytest.RenderText(node.Column(Render(src, width, 7)...), width, height)
+}
+
+func TestParagraphReflow(t *testing.T) {
+	got := frame("This paragraph spans\ntwo source lines and reflows.", 16, 4)
+	want := "This paragraph\n" +
+		"spans two source\n" +
+		"lines and\n" +
+		"reflows."
+	if got != want {
+		t.Fatalf("reflow:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Styled spans split only at the wrap point.
+	nodes := Render("plain **bold words** end", 12, 7)
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(nodes))
+	}
+	first, second := nodes[0].Children, nodes[1].Children
+	if first[1].Props.Text != "bold" || first[1].Props.Style&node.Bold == 0 {
+		t.Errorf("first line bold span = %+v", first[1].Props)
+	}
+	if second[0].Props.Text != "words" || second[0].Props.Style&node.Bold == 0 {
+		t.Errorf("second line bold span = %+v", second[0].Props)
+	}
+}
+
+func TestHardBreakAndLongWord(t *testing.T) {
+	got := frame("one  \ntwo\\\nthree abcdefghij", 6, 5)
+	want := "one\n" +
+		"two\n" +
+		"three\n" +
+		"abcdef\n" +
+		"ghij"
+	if got != want {
+		t.Fatalf("breaks:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSetextAndHeadingStyles(t *testing.T) {
+	nodes := Render("Top\n===\nSub\n---\n### Third\n#### Fourth", 40, 7)
+	if len(nodes) != 4 {
+		t.Fatalf("expected 4 headings, got %d", len(nodes))
+	}
+	colors := DefaultColors(7)
+	want := []struct {
+		text  string
+		style node.StyleFlags
+		fg    node.Color
+	}{
+		{"Top", node.Bold | node.Underline, colors.Heading},
+		{"Sub", node.Bold, colors.Heading},
+		{"Third", node.Bold | node.Italic, colors.Heading},
+		{"Fourth", node.Bold, 7},
+	}
+	for i, w := range want {
+		p := nodes[i].Children[0].Props
+		if p.Text != w.text || p.Style != w.style || p.FG != w.fg {
+			t.Errorf("heading %d = %q style %v fg %v, want %q style %v fg %v", i, p.Text, p.Style, p.FG, w.text, w.style, w.fg)
+		}
+	}
+}
+
+func TestNestedListHangingIndent(t *testing.T) {
+	src := "- first item wraps here\n  - nested\n    continued\n- second"
+	got := frame(src, 18, 5)
+	want := "  • first item\n" +
+		"    wraps here\n" +
+		"      ◦ nested\n" +
+		"        continued\n" +
+		"  • second"
+	if got != want {
+		t.Fatalf("nested list:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestOrderedListNumbering(t *testing.T) {
+	src := "8. eight\n9. nine\n\n1. ten"
+	got := frame(src, 20, 4)
+	want := "   8. eight\n" +
+		"   9. nine\n" +
+		"\n" +
+		"  10. ten"
+	if got != want {
+		t.Fatalf("ordered list:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestListEndsAtUnindentedParagraph(t *testing.T) {
+	got := frame("- item\n\nafter", 20, 3)
+	if got != "  • item\n\nafter" {
+		t.Fatalf("list end:\n%s", got)
+	}
+	got = frame("- item\nlazy line", 20, 1)
+	if got != "  • item lazy line" {
+		t.Fatalf("lazy continuation:\n%s", got)
+	}
+}
+
+func TestQuoteNestsBlocks(t *testing.T) {
+	got := frame("> # Title\n> - point\n>\n> text", 20, 4)
+	want := "  │ Title\n" +
+		"  │   • point\n" +
+		"  │\n" +
+		"  │ text"
+	if got != want {
+		t.Fatalf("quote:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTableAlignment(t *testing.T) {
+	src := "| Name | Qty | Note |\n|:-----|----:|:----:|\n| apple | 3 | ok |\n| fig | 12 |"
+	got := frame(src, 40, 4)
+	want := "Name  │ Qty │ Note\n" +
+		"──────┼─────┼─────\n" +
+		"apple │   3 │  ok\n" +
+		"fig   │  12 │"
+	if got != want {
+		t.Fatalf("table:\n%s\nwant:\n%s", got, want)
+	}
+
+	nodes := Render(src, 40, 7)
+	if nodes[0].Children[0].Props.Style&node.Bold == 0 {
+		t.Error("header cells should be bold")
+	}
+}
+
+func TestTableShrinksToWidth(t *testing.T) {
+	src := "a | b\n--|--\nshort | a much longer cell"
+	got := frame(src, 16, 3)
+	want := "a     │ b\n" +
+		"──────┼─────────\n" +
+		"short │ a much …"
+	if got != want {
+		t.Fatalf("narrow table:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestIndentedAndTildeCode(t *testing.T) {
+	nodes := Render("    x := 1\n\n    y := 2\n\n~~~~\nraw\n~~~\n~~~~", 40, 7)
+	if len(nodes) != 3 {
+		t.Fatalf("expected code, blank, code; got %d nodes", len(nodes))
+	}
+	code := nodes[0].Children[0].Children
+	if len(code) != 3 || code[0].Props.Text != "x := 1" || code[2].Props.Text != "y := 2" {
+		t.Errorf("indented code lines = %v", code)
+	}
+	fenced := nodes[2].Children[0].Children
+	if len(fenced) != 2 || fenced[1].Props.Text != "~~~" {
+		t.Errorf("a shorter fence should not close a longer one: %v", fenced)
+	}
+}
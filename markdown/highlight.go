@@ -0,0 +1,196 @@
+package markdown
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/stukennedy/tooey/node"
+)
+
+// TokenClass classifies a span of highlighted code.
+type TokenClass int
+
+const (
+	TokenText TokenClass = iota
+	TokenKeyword
+	TokenType // builtin types and functions
+	TokenString
+	TokenNumber
+	TokenComment
+	TokenKey      // object keys in JSON and YAML
+	TokenVariable // shell variables
+	TokenInserted // diff additions
+	TokenDeleted  // diff removals
+	TokenMeta     // diff headers and hunk markers
+)
+
+// Token is a run of code text in one class.
+type Token struct {
+	Class TokenClass
+	Text  string
+}
+
+// Highlighter splits the lines of a code block into tokens, returning
+// one token slice per line. It sees the whole block so constructs such
+// as block comments can span lines.
+type Highlighter interface {
+	Highlight(lines []string) [][]Token
+}
+
+// HighlighterFunc adapts a function to the Highlighter interface.
+type HighlighterFunc func(lines []string) [][]Token
+
+// Highlight calls f(lines).
+func (f HighlighterFunc) Highlight(lines []string) [][]Token { return f(lines) }
+
+var (
+	highlightersMu sync.RWMutex
+	highlighters   = map[string]Highlighter{}
+)
+
+func init() {
+	RegisterHighlighter(goLexer, "go", "golang")
+	RegisterHighlighter(jsonLexer, "json")
+	RegisterHighlighter(shellLexer, "sh", "bash", "shell", "zsh", "console")
+	RegisterHighlighter(HighlighterFunc(lexYAML), "yaml", "yml")
+	RegisterHighlighter(HighlighterFunc(lexDiff), "diff", "patch")
+}
+
+// RegisterHighlighter makes h the highlighter for fenced code blocks
+// whose info string names one of langs (case-insensitive), replacing
+// any earlier registration.
+func RegisterHighlighter(h Highlighter, langs ...string) {
+	highlightersMu.Lock()
+	defer highlightersMu.Unlock()
+	for _, lang := range langs {
+		highlighters[strings.ToLower(lang)] = h
+	}
+}
+
+// HighlighterFor returns the highlighter registered for lang, or nil.
+func HighlighterFor(lang string) Highlighter {
+	highlightersMu.RLock()
+	defer highlightersMu.RUnlock()
+	return highlighters[strings.ToLower(lang)]
+}
+
+// SyntaxTheme maps token classes to colors. Classes left at zero use
+// ColorScheme.Code.
+type SyntaxTheme struct {
+	Keyword  node.Color
+	Type     node.Color
+	String   node.Color
+	Number   node.Color
+	Comment  node.Color
+	Key      node.Color
+	Variable node.Color
+	Inserted node.Color
+	Deleted  node.Color
+	Meta     node.Color
+}
+
+// DefaultSyntaxTheme returns the theme used by DefaultColors.
+func DefaultSyntaxTheme() SyntaxTheme {
+	return SyntaxTheme{
+		Keyword:  204, // pink
+		Type:     81,  // cyan
+		String:   150, // green
+		Number:   215, // orange
+		Comment:  244, // gray
+		Key:      117, // light blue
+		Variable: 180, // tan
+		Inserted: 114, // green
+		Deleted:  210, // red
+		Meta:     110, // steel blue
+	}
+}
+
+// Color returns the color for a token class, or 0 for plain text.
+func (t SyntaxTheme) Color(c TokenClass) node.Color {
+	switch c {
+	case TokenKeyword:
+		return t.Keyword
+	case TokenType:
+		return t.Type
+	case TokenString:
+		return t.String
+	case TokenNumber:
+		return t.Number
+	case TokenComment:
+		return t.Comment
+	case TokenKey:
+		return t.Key
+	case TokenVariable:
+		return t.Variable
+	case TokenInserted:
+		return t.Inserted
+	case TokenDeleted:
+		return t.Deleted
+	case TokenMeta:
+		return t.Meta
+	}
+	return 0
+}
+
+// fenceLang returns the language named by a code fence's info string,
+// e.g. "go" for "```go title=main.go".
+func fenceLang(fence string) string {
+	info := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(fence), "`"))
+	if i := strings.IndexAny(info, " \t{"); i >= 0 {
+		info = info[:i]
+	}
+	return info
+}
+
+// renderCode builds the node rows for a fenced code block, highlighting
+// them when a highlighter is registered for lang.
+func renderCode(lines []string, lang string, colors ColorScheme) []node.Node {
+	h := HighlighterFor(lang)
+	if h == nil || len(lines) == 0 {
+		rows := make([]node.Node, len(lines))
+		for i, line := range lines {
+			rows[i] = node.TextStyled(line, colors.Code, colors.CodeBG, 0)
+		}
+		return rows
+	}
+	tokens := h.Highlight(lines)
+	rows := make([]node.Node, len(lines))
+	for i := range lines {
+		var spans []node.Node
+		if i < len(tokens) {
+			for _, tok := range tokens[i] {
+				fg := colors.Syntax.Color(tok.Class)
+				if fg == 0 {
+					fg = colors.Code
+				}
+				spans = append(spans, node.TextStyled(tok.Text, fg, colors.CodeBG, 0).WithNoWrap())
+			}
+		}
+		// The flexible tail carries the code background to the edge,
+		// as a single full-width text line would.
+		rows[i] = node.Row(append(spans, node.Bar("", colors.Code, colors.CodeBG, 0))...)
+	}
+	return rows
+}
+
+// lexDiff classifies whole lines of a unified diff.
+func lexDiff(lines []string) [][]Token {
+	out := make([][]Token, len(lines))
+	for i, line := range lines {
+		class := TokenText
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"),
+			strings.HasPrefix(line, "@@"), strings.HasPrefix(line, "diff "),
+			strings.HasPrefix(line, "index "):
+			class = TokenMeta
+		case strings.HasPrefix(line, "+"):
+			class = TokenInserted
+		case strings.HasPrefix(line, "-"):
+			class = TokenDeleted
+		}
+		if line != "" {
+			out[i] = []Token{{Class: class, Text: line}}
+		}
+	}
+	return out
+}
@@ -0,0 +1,149 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stukennedy/tooey/node"
+)
+
+// classes returns the class and text of each token of the first line,
+// dropping plain text so tests can focus on what gets colored.
+func classes(h Highlighter, lines ...string) []Token {
+	var out []Token
+	for _, line := range h.Highlight(lines) {
+		for _, tok := range line {
+			if tok.Class != TokenText {
+				out = append(out, tok)
+			}
+		}
+	}
+	return out
+}
+
+func TestHighlightGo(t *testing.T) {
+	got := classes(HighlighterFor("go"),
+		`func main() { x := "hi\"" + 'a' // note`,
+		"s := `raw",
+		"still raw` /* block",
+		"comment */ return 42")
+	want := []Token{
+		{TokenKeyword, "func"},
+		{TokenString, `"hi\""`},
+		{TokenString, "'a'"},
+		{TokenComment, "// note"},
+		{TokenString, "`raw"},
+		{TokenString, "still raw`"},
+		{TokenComment, "/* block"},
+		{TokenComment, "comment */"},
+		{TokenKeyword, "return"},
+		{TokenNumber, "42"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("go tokens:\n got %v\nwant %v", got, want)
+	}
+	if got := classes(HighlighterFor("golang"), "var n int = len(s)"); len(got) != 3 || got[1] != (Token{TokenType, "int"}) {
+		t.Errorf("golang alias / builtins = %v", got)
+	}
+}
+
+func TestHighlightJSON(t *testing.T) {
+	got := classes(HighlighterFor("JSON"), `{"name": "tooey", "stars": 10, "ok": true, "x": null}`)
+	want := []Token{
+		{TokenKey, `"name"`}, {TokenString, `"tooey"`},
+		{TokenKey, `"stars"`}, {TokenNumber, "10"},
+		{TokenKey, `"ok"`}, {TokenKeyword, "true"},
+		{TokenKey, `"x"`}, {TokenKeyword, "null"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("json tokens:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestHighlightShell(t *testing.T) {
+	got := classes(HighlighterFor("bash"), `if [ -n "$HOME" ]; then echo ${USER}#x $? # done`)
+	want := []Token{
+		{TokenKeyword, "if"},
+		{TokenString, `"$HOME"`},
+		{TokenKeyword, "then"},
+		{TokenType, "echo"},
+		{TokenVariable, "${USER}"},
+		{TokenVariable, "$?"},
+		{TokenComment, "# done"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("shell tokens:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestHighlightYAML(t *testing.T) {
+	got := classes(HighlighterFor("yml"),
+		"build-args:",
+		"  - cache from: 'main' # comment",
+		"  retries: 3",
+		"  on: {debug: true}")
+	want := []Token{
+		{TokenKey, "build-args"},
+		{TokenKey, "cache from"}, {TokenString, "'main'"}, {TokenComment, "# comment"},
+		{TokenKey, "retries"}, {TokenNumber, "3"},
+		{TokenKey, "on"}, {TokenKey, "debug"}, {TokenKeyword, "true"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("yaml tokens:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestHighlightDiff(t *testing.T) {
+	lines := HighlighterFor("diff").Highlight([]string{"--- a/x", "+++ b/x", "@@ -1 +1 @@", " same", "-old", "+new", ""})
+	want := []TokenClass{TokenMeta, TokenMeta, TokenMeta, TokenText, TokenDeleted, TokenInserted}
+	for i, class := range want {
+		if len(lines[i]) != 1 || lines[i][0].Class != class {
+			t.Errorf("line %d = %v, want class %v", i, lines[i], class)
+		}
+	}
+	if lines[6] != nil {
+		t.Errorf("empty line = %v, want no tokens", lines[6])
+	}
+}
+
+func TestRegisterHighlighter(t *testing.T) {
+	upper := HighlighterFunc(func(lines []string) [][]Token {
+		out := make([][]Token, len(lines))
+		for i, l := range lines {
+			out[i] = []Token{{TokenKeyword, l}}
+		}
+		return out
+	})
+	RegisterHighlighter(upper, "Shout")
+	defer RegisterHighlighter(nil, "shout")
+
+	colors := DefaultColors(7)
+	nodes := RenderWithColors("```shout title=x\nhey\n```", 40, colors)
+	row := nodes[0].Children[0].Children[0]
+	if row.Type != node.RowNode {
+		t.Fatalf("highlighted line should be a Row, got %v", row.Type)
+	}
+	span := row.Children[0]
+	if span.Props.Text != "hey" || span.Props.FG != colors.Syntax.Keyword || span.Props.BG != colors.CodeBG {
+		t.Errorf("span = %q fg %v bg %v", span.Props.Text, span.Props.FG, span.Props.BG)
+	}
+}
+
+func TestCodeBlockUnknownLangIsPlain(t *testing.T) {
+	colors := DefaultColors(7)
+	nodes := RenderWithColors("```brainfuck\n+++.\n```", 40, colors)
+	line := nodes[0].Children[0].Children[0]
+	if line.Type != node.TextNode || line.Props.FG != colors.Code {
+		t.Errorf("unknown language should render plain code, got %+v", line)
+	}
+}
+
+func TestSyntaxThemeFallsBackToCode(t *testing.T) {
+	colors := DefaultColors(7)
+	colors.Syntax = SyntaxTheme{}
+	nodes := RenderWithColors("```go\nreturn\n```", 40, colors)
+	span := nodes[0].Children[0].Children[0].Children[0]
+	if span.Props.FG != colors.Code {
+		t.Errorf("unset theme color = %v, want Code %v", span.Props.FG, colors.Code)
+	}
+}
@@ -0,0 +1,263 @@
+package markdown
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// codeLexer is a small table-driven lexer covering the C-like and
+// line-oriented languages highlighted out of the box. It recognizes
+// comments, quoted strings, numbers, identifiers (classed as keywords,
+// types or keys) and, optionally, shell variables.
+type codeLexer struct {
+	keywords map[string]bool
+	types    map[string]bool
+
+	lineComment  string    // "//" or "#"
+	blockComment [2]string // opening and closing markers, if any
+	quotes       string    // characters that open a string
+	multiline    string    // quotes whose strings may span lines
+	variables    bool      // $NAME, ${NAME} and $1 are TokenVariable
+	// keys marks an identifier or string followed by ':' as TokenKey.
+	keys bool
+}
+
+// Highlight implements Highlighter.
+func (lx *codeLexer) Highlight(lines []string) [][]Token {
+	out := make([][]Token, len(lines))
+	// open is the closing marker of a comment or string left open at
+	// the end of the previous line.
+	var open string
+	var openClass TokenClass
+	for i, line := range lines {
+		var ts tokens
+		pos := 0
+		if open != "" {
+			end := strings.Index(line, open)
+			if end < 0 {
+				ts.add(openClass, line)
+				out[i] = ts
+				continue
+			}
+			pos = end + len(open)
+			ts.add(openClass, line[:pos])
+			open = ""
+		}
+		for pos < len(line) {
+			rest := line[pos:]
+			c := rest[0]
+			switch {
+			case lx.lineComment != "" && strings.HasPrefix(rest, lx.lineComment) &&
+				(lx.lineComment != "#" || pos == 0 || line[pos-1] == ' ' || line[pos-1] == '\t'):
+				ts.add(TokenComment, rest)
+				pos = len(line)
+			case lx.blockComment[0] != "" && strings.HasPrefix(rest, lx.blockComment[0]):
+				n := len(lx.blockComment[0])
+				if end := strings.Index(rest[n:], lx.blockComment[1]); end >= 0 {
+					n += end + len(lx.blockComment[1])
+				} else {
+					n = len(rest)
+					open, openClass = lx.blockComment[1], TokenComment
+				}
+				ts.add(TokenComment, rest[:n])
+				pos += n
+			case strings.IndexByte(lx.quotes, c) >= 0:
+				n, closed := quotedLen(rest)
+				if !closed && strings.IndexByte(lx.multiline, c) >= 0 {
+					open, openClass = string(c), TokenString
+				}
+				class := TokenString
+				if lx.keys && isKeyFollow(rest[n:]) {
+					class = TokenKey
+				}
+				ts.add(class, rest[:n])
+				pos += n
+			case lx.variables && c == '$' && len(rest) > 1:
+				n := variableLen(rest)
+				ts.add(TokenVariable, rest[:n])
+				pos += n
+			case isDigit(c):
+				n := 1
+				for n < len(rest) && (isIdent(rest[n]) || rest[n] == '.') {
+					n++
+				}
+				ts.add(TokenNumber, rest[:n])
+				pos += n
+			case isIdentStart(c):
+				n := 1
+				for n < len(rest) && isIdent(rest[n]) {
+					n++
+				}
+				word := rest[:n]
+				class := TokenText
+				switch {
+				case lx.keys && isKeyFollow(rest[n:]):
+					class = TokenKey
+				case lx.keywords[word]:
+					class = TokenKeyword
+				case lx.types[word]:
+					class = TokenType
+				}
+				ts.add(class, word)
+				pos += n
+			default:
+				_, n := utf8.DecodeRuneInString(rest)
+				ts.add(TokenText, rest[:n])
+				pos += n
+			}
+		}
+		out[i] = ts
+	}
+	return out
+}
+
+// tokens accumulates a line's tokens, merging neighbours of one class.
+type tokens []Token
+
+func (ts *tokens) add(class TokenClass, text string) {
+	if text == "" {
+		return
+	}
+	if n := len(*ts); n > 0 && (*ts)[n-1].Class == class {
+		(*ts)[n-1].Text += text
+		return
+	}
+	*ts = append(*ts, Token{Class: class, Text: text})
+}
+
+// quotedLen returns the length of the string literal at the start of s,
+// honouring backslash escapes, and whether it was closed on this line.
+func quotedLen(s string) (int, bool) {
+	q := s[0]
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if q != '`' {
+				i++
+			}
+		case q:
+			return i + 1, true
+		}
+	}
+	return len(s), false
+}
+
+// variableLen returns the length of the shell variable at the start of
+// s, which begins with '$'.
+func variableLen(s string) int {
+	if s[1] == '{' {
+		if end := strings.IndexByte(s, '}'); end >= 0 {
+			return end + 1
+		}
+		return len(s)
+	}
+	if !isIdent(s[1]) {
+		// Special parameters such as $? and $#.
+		if strings.IndexByte("?#@*!$-", s[1]) >= 0 {
+			return 2
+		}
+		return 1
+	}
+	n := 1
+	for n < len(s) && isIdent(s[n]) {
+		n++
+	}
+	return n
+}
+
+// isKeyFollow reports whether s (what follows a word or string) starts
+// with optional spaces and a ':' that ends a mapping key.
+func isKeyFollow(s string) bool {
+	s = strings.TrimLeft(s, " \t")
+	return strings.HasPrefix(s, ":") && (len(s) == 1 || s[1] == ' ' || s[1] == '\t')
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIdent(c byte) bool { return isIdentStart(c) || isDigit(c) }
+
+// words builds a lookup set from a space-separated list.
+func words(s string) map[string]bool {
+	m := map[string]bool{}
+	for _, w := range strings.Fields(s) {
+		m[w] = true
+	}
+	return m
+}
+
+var goLexer = &codeLexer{
+	keywords: words(`break case chan const continue default defer else fallthrough
+		for func go goto if import interface map package range return select
+		struct switch type var`),
+	types: words(`any bool byte comparable complex64 complex128 error float32
+		float64 int int8 int16 int32 int64 rune string uint uint8 uint16 uint32
+		uint64 uintptr true false iota nil append cap clear close complex copy
+		delete imag len make max min new panic print println real recover`),
+	lineComment:  "//",
+	blockComment: [2]string{"/*", "*/"},
+	quotes:       "\"'`",
+	multiline:    "`",
+}
+
+var jsonLexer = &codeLexer{
+	keywords: words("true false null"),
+	quotes:   `"`,
+	keys:     true,
+}
+
+var shellLexer = &codeLexer{
+	keywords: words(`if then else elif fi for while until do done case esac in
+		function return export local readonly unset shift exit break continue`),
+	types:       words("echo printf cd pwd source set test read eval exec trap"),
+	lineComment: "#",
+	quotes:      `"'`,
+	variables:   true,
+}
+
+// yamlValues lexes what follows a YAML mapping key.
+var yamlValues = &codeLexer{
+	keywords:    words("true false null yes no on off True False Null Yes No"),
+	lineComment: "#",
+	quotes:      `"'`,
+	keys:        true, // quoted keys and flow mappings
+}
+
+// lexYAML classes a line's mapping key (which, unlike a JSON key, may
+// contain spaces and dashes) and lexes the rest as a value.
+func lexYAML(lines []string) [][]Token {
+	out := make([][]Token, len(lines))
+	for i, line := range lines {
+		var ts tokens
+		body := strings.TrimLeft(line, " ")
+		if strings.HasPrefix(body, "- ") {
+			body = strings.TrimLeft(body[2:], " ")
+		}
+		ts.add(TokenText, line[:len(line)-len(body)])
+		if key, ok := yamlKey(body); ok {
+			ts.add(TokenKey, key)
+			body = body[len(key):]
+		}
+		for _, tok := range yamlValues.Highlight([]string{body})[0] {
+			ts.add(tok.Class, tok.Text)
+		}
+		out[i] = ts
+	}
+	return out
+}
+
+// yamlKey returns the plain mapping key at the start of s, if any.
+func yamlKey(s string) (string, bool) {
+	if s == "" || strings.IndexByte("#'\"{[", s[0]) >= 0 {
+		return "", false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] == ':' && isKeyFollow(s[i:]) {
+			return s[:i], true
+		}
+	}
+	return "", false
+}
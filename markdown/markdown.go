@@ -18,6 +18,10 @@ type ColorScheme struct {
 	CheckOn node.Color
 	CheckOff node.Color
 	Rule    node.Color
+
+	// Syntax colors highlighted code in fences whose info string names
+	// a registered language (see RegisterHighlighter).
+	Syntax SyntaxTheme
 }
 
 // DefaultColors returns a sensible default color scheme.
@@ -33,6 +37,7 @@ func DefaultColors(fg node.Color) ColorScheme {
 		CheckOn:  34,  // green
 		CheckOff: 245, // gray
 		Rule:     240, // dim gray
+		Syntax:   DefaultSyntaxTheme(),
 	}
 }
 
@@ -41,115 +46,12 @@ func Render(text string, width int, fg node.Color) []node.Node {
 	return RenderWithColors(text, width, DefaultColors(fg))
 }
 
-// RenderWithColors converts markdown text into styled node.Node trees.
+// RenderWithColors converts markdown text into styled node.Node trees,
+// one node per visual line. Paragraphs and list items are reflowed to
+// width (0 disables wrapping); fenced and indented code blocks render
+// as a bordered Box.
 func RenderWithColors(text string, width int, colors ColorScheme) []node.Node {
-	lines := strings.Split(text, "\n")
-	var nodes []node.Node
-	i := 0
-	for i < len(lines) {
-		line := lines[i]
-
-		// Code fence
-		if strings.HasPrefix(strings.TrimSpace(line), "```") {
-			i++
-			var codeLines []node.Node
-			for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
-				codeLines = append(codeLines, node.TextStyled(lines[i], colors.Code, colors.CodeBG, 0))
-				i++
-			}
-			if i < len(lines) {
-				i++ // skip closing fence
-			}
-			if len(codeLines) == 0 {
-				codeLines = append(codeLines, node.TextStyled(" ", colors.Code, colors.CodeBG, 0))
-			}
-			nodes = append(nodes, node.Box(node.BorderRounded, node.Column(codeLines...)))
-			continue
-		}
-
-		// Horizontal rule
-		trimmed := strings.TrimSpace(line)
-		if isHorizontalRule(trimmed) {
-			if width <= 0 {
-				width = 40
-			}
-			nodes = append(nodes, node.TextStyled(strings.Repeat("─", width), colors.Rule, 0, 0))
-			i++
-			continue
-		}
-
-		// Heading
-		if level, content := parseHeading(line); level > 0 {
-			spans := parseInline(content, colors.Heading, colors)
-			for idx := range spans {
-				spans[idx].Props.Style |= node.Bold
-			}
-			nodes = append(nodes, node.Row(spans...))
-			i++
-			continue
-		}
-
-		// Checkbox list items
-		if rest, checked := parseCheckbox(line); checked >= 0 {
-			if checked == 1 {
-				prefix := node.TextStyled("  ✔ ", colors.CheckOn, 0, 0)
-				spans := parseInline(rest, colors.Text, colors)
-				nodes = append(nodes, node.Row(append([]node.Node{prefix}, spans...)...))
-			} else {
-				prefix := node.TextStyled("  ☐ ", colors.CheckOff, 0, 0)
-				spans := parseInline(rest, colors.Text, colors)
-				for idx := range spans {
-					spans[idx].Props.Style |= node.Dim
-				}
-				nodes = append(nodes, node.Row(append([]node.Node{prefix}, spans...)...))
-			}
-			i++
-			continue
-		}
-
-		// Bullet list
-		if rest, ok := parseBullet(line); ok {
-			prefix := node.TextStyled("  • ", colors.Bullet, 0, 0)
-			spans := parseInline(rest, colors.Text, colors)
-			nodes = append(nodes, node.Row(append([]node.Node{prefix}, spans...)...))
-			i++
-			continue
-		}
-
-		// Numbered list
-		if num, rest, ok := parseNumbered(line); ok {
-			prefix := node.TextStyled("  "+num+". ", colors.Bullet, 0, 0)
-			spans := parseInline(rest, colors.Text, colors)
-			nodes = append(nodes, node.Row(append([]node.Node{prefix}, spans...)...))
-			i++
-			continue
-		}
-
-		// Blockquote
-		if rest, ok := parseBlockquote(line); ok {
-			prefix := node.TextStyled("  │ ", colors.Quote, 0, 0)
-			spans := parseInline(rest, colors.Text, colors)
-			for idx := range spans {
-				spans[idx].Props.Style |= node.Italic
-			}
-			nodes = append(nodes, node.Row(append([]node.Node{prefix}, spans...)...))
-			i++
-			continue
-		}
-
-		// Empty line
-		if trimmed == "" {
-			nodes = append(nodes, node.Text(""))
-			i++
-			continue
-		}
-
-		// Plain paragraph
-		spans := parseInline(line, colors.Text, colors)
-		nodes = append(nodes, node.Row(spans...))
-		i++
-	}
-	return nodes
+	return renderBlocks(parseBlocks(splitLines(text)), width, colors, 0)
 }
 
 func isHorizontalRule(s string) bool {
@@ -179,57 +81,6 @@ func parseHeading(line string) (int, string) {
 	return 0, ""
 }
 
-func parseCheckbox(line string) (string, int) {
-	t := strings.TrimSpace(line)
-	for _, prefix := range []string{"- ", "* "} {
-		if strings.HasPrefix(t, prefix) {
-			rest := t[len(prefix):]
-			if strings.HasPrefix(rest, "[x] ") || strings.HasPrefix(rest, "[X] ") {
-				return rest[4:], 1
-			}
-			if strings.HasPrefix(rest, "[ ] ") {
-				return rest[4:], 0
-			}
-		}
-	}
-	return "", -1
-}
-
-func parseBullet(line string) (string, bool) {
-	t := strings.TrimSpace(line)
-	for _, prefix := range []string{"- ", "* ", "+ "} {
-		if strings.HasPrefix(t, prefix) {
-			return t[len(prefix):], true
-		}
-	}
-	return "", false
-}
-
-func parseNumbered(line string) (string, string, bool) {
-	t := strings.TrimSpace(line)
-	for i, c := range t {
-		if c >= '0' && c <= '9' {
-			continue
-		}
-		if c == '.' && i > 0 && i+1 < len(t) && t[i+1] == ' ' {
-			return t[:i], t[i+2:], true
-		}
-		break
-	}
-	return "", "", false
-}
-
-func parseBlockquote(line string) (string, bool) {
-	t := strings.TrimSpace(line)
-	if strings.HasPrefix(t, "> ") {
-		return t[2:], true
-	}
-	if t == ">" {
-		return "", true
-	}
-	return "", false
-}
-
 // parseInline scans text for inline markdown and returns styled nodes.
 func parseInline(text string, defaultFG node.Color, colors ColorScheme) []node.Node {
 	var nodes []node.Node
@@ -277,6 +128,15 @@ func parseInline(text string, defaultFG node.Color, colors ColorScheme) []node.N
 				continue
 			}
 		}
+		// Strikethrough ~~
+		if i+1 < len(runes) && runes[i] == '~' && runes[i+1] == '~' {
+			if end := findClose(runes, i+2, "~~"); end > i+2 {
+				flush(defaultFG, 0)
+				nodes = append(nodes, node.TextStyled(string(runes[i+2:end]), defaultFG, 0, node.Strikethrough))
+				i = end + 2
+				continue
+			}
+		}
 		// Inline code `
 		if runes[i] == '`' {
 			if end := findClose(runes, i+1, "`"); end >= 0 {
@@ -288,9 +148,9 @@ func parseInline(text string, defaultFG node.Color, colors ColorScheme) []node.N
 		}
 		// Link [text](url)
 		if runes[i] == '[' {
-			if linkText, end := parseLink(runes, i); end >= 0 {
+			if linkText, url, end := parseLink(runes, i); end >= 0 {
 				flush(defaultFG, 0)
-				nodes = append(nodes, node.TextStyled(linkText, colors.Link, 0, node.Underline))
+				nodes = append(nodes, node.TextStyled(linkText, colors.Link, 0, node.Underline).WithLink(url))
 				i = end
 				continue
 			}
@@ -319,7 +179,7 @@ func findClose(runes []rune, start int, marker string) int {
 	return -1
 }
 
-func parseLink(runes []rune, start int) (string, int) {
+func parseLink(runes []rune, start int) (string, string, int) {
 	// [text](url)
 	closeB := -1
 	for i := start + 1; i < len(runes); i++ {
@@ -329,7 +189,7 @@ func parseLink(runes []rune, start int) (string, int) {
 		}
 	}
 	if closeB < 0 || closeB+1 >= len(runes) || runes[closeB+1] != '(' {
-		return "", -1
+		return "", "", -1
 	}
 	closeP := -1
 	for i := closeB + 2; i < len(runes); i++ {
@@ -339,7 +199,9 @@ func parseLink(runes []rune, start int) (string, int) {
 		}
 	}
 	if closeP < 0 {
-		return "", -1
+		return "", "", -1
 	}
-	return string(runes[start+1 : closeB]), closeP + 1
+	// Drop an optional title: [text](url "title").
+	url, _, _ := strings.Cut(strings.TrimSpace(string(runes[closeB+2:closeP])), " ")
+	return string(runes[start+1 : closeB]), url, closeP + 1
 }
@@ -96,6 +96,25 @@ func TestLink(t *testing.T) {
 	if child.Props.Style&node.Underline == 0 {
 		t.Error("expected underline for link")
 	}
+	if child.Props.Link != "http://x" {
+		t.Errorf("expected link URL, got %q", child.Props.Link)
+	}
+
+	nodes = Render(`see [docs](https://x.dev/a "Docs") now`, 40, 7)
+	if link := nodes[0].Children[1].Props.Link; link != "https://x.dev/a" {
+		t.Errorf("titled link URL = %q", link)
+	}
+}
+
+func TestStrikethrough(t *testing.T) {
+	nodes := Render("keep ~~drop~~ ~~", 40, 7)
+	spans := nodes[0].Children
+	if len(spans) != 3 || spans[1].Props.Text != "drop" || spans[1].Props.Style&node.Strikethrough == 0 {
+		t.Fatalf("spans = %+v", spans)
+	}
+	if spans[2].Props.Text != " ~~" {
+		t.Errorf("unmatched ~~ should stay literal, got %q", spans[2].Props.Text)
+	}
 }
 
 func TestBulletList(t *testing.T) {
@@ -177,6 +196,10 @@ func TestCheckboxChecked(t *testing.T) {
 	if !strings.Contains(nodes[0].Children[0].Props.Text, "✔") {
 		t.Error("expected check mark")
 	}
+	// Done items are struck through
+	if nodes[0].Children[1].Props.Style&node.Strikethrough == 0 {
+		t.Error("expected strikethrough style for checked item")
+	}
 }
 
 func TestCheckboxUnchecked(t *testing.T) {
@@ -0,0 +1,377 @@
+package markdown
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/textwidth"
+)
+
+// bullets are the list markers used at successive nesting depths.
+var bullets = []string{"•", "◦", "▪"}
+
+// renderBlocks renders blocks as a flat list of line nodes: one Row
+// per visual line, except code blocks, which render as a bordered Box.
+// depth is the list nesting depth, used to vary bullets.
+func renderBlocks(blocks []block, width int, colors ColorScheme, depth int) []node.Node {
+	var nodes []node.Node
+	for _, b := range blocks {
+		nodes = append(nodes, renderBlock(b, width, colors, depth)...)
+	}
+	return nodes
+}
+
+func renderBlock(b block, width int, colors ColorScheme, depth int) []node.Node {
+	switch b.kind {
+	case paragraphBlock:
+		return renderInline(b.text, width, colors.Text, 0, colors)
+	case headingBlock:
+		fg, style := colors.Heading, headingStyle(b.level)
+		if b.level > 3 {
+			fg = colors.Text
+		}
+		return renderInline(b.text, width, fg, style, colors)
+	case ruleBlock:
+		if width <= 0 {
+			width = 40
+		}
+		return []node.Node{node.TextStyled(strings.Repeat("─", width), colors.Rule, 0, 0)}
+	case codeBlock:
+		codeLines := renderCode(b.lines, b.lang, colors)
+		if len(codeLines) == 0 {
+			codeLines = append(codeLines, node.TextStyled(" ", colors.Code, colors.CodeBG, 0))
+		}
+		return []node.Node{node.Box(node.BorderRounded, node.Column(codeLines...))}
+	case quoteBlock:
+		rows := addStyle(renderBlocks(b.children, innerWidth(width, 4), colors, depth), node.Italic)
+		bar := node.TextStyled("  │ ", colors.Quote, 0, 0)
+		return prefixRows(rows, bar, bar)
+	case listBlock:
+		return renderList(b, width, colors, depth)
+	case tableBlock:
+		return renderTable(b, width, colors)
+	}
+	return []node.Node{node.Text("")}
+}
+
+// headingStyle distinguishes heading levels: H1 is underlined, H3
+// italic, and the rest plain bold.
+func headingStyle(level int) node.StyleFlags {
+	switch level {
+	case 1:
+		return node.Bold | node.Underline
+	case 3:
+		return node.Bold | node.Italic
+	}
+	return node.Bold
+}
+
+// innerWidth returns the width left after an n-cell prefix. A width of
+// zero or less means "don't wrap" and is passed through.
+func innerWidth(width, n int) int {
+	if width <= 0 {
+		return width
+	}
+	return max(width-n, 1)
+}
+
+// renderInline parses inline markup and wraps it to width, honouring
+// hard line breaks ("\n").
+func renderInline(text string, width int, fg node.Color, style node.StyleFlags, colors ColorScheme) []node.Node {
+	var rows []node.Node
+	for _, seg := range strings.Split(text, "\n") {
+		spans := parseInline(seg, fg, colors)
+		for i := range spans {
+			spans[i].Props.Style |= style
+		}
+		if len(spans) == 0 {
+			rows = append(rows, node.Text(""))
+			continue
+		}
+		for _, line := range wrapSpans(spans, width) {
+			rows = append(rows, node.Row(line...))
+		}
+	}
+	return rows
+}
+
+// renderList renders list items with their markers and hangs wrapped
+// and nested content under the item's text.
+func renderList(b block, width int, colors ColorScheme, depth int) []node.Node {
+	numW := len(strconv.Itoa(b.start + len(b.items) - 1))
+	var nodes []node.Node
+	for i, it := range b.items {
+		var marker node.Node
+		switch {
+		case it.task == 1:
+			marker = node.TextStyled("  ✔ ", colors.CheckOn, 0, 0)
+		case it.task == 0:
+			marker = node.TextStyled("  ☐ ", colors.CheckOff, 0, 0)
+		case b.ordered:
+			num := strconv.Itoa(b.start + i)
+			marker = node.TextStyled("  "+strings.Repeat(" ", numW-len(num))+num+". ", colors.Bullet, 0, 0)
+		default:
+			marker = node.TextStyled("  "+bullets[depth%len(bullets)]+" ", colors.Bullet, 0, 0)
+		}
+		markerW := textwidth.String(marker.Props.Text)
+		rows := renderBlocks(it.blocks, innerWidth(width, markerW), colors, depth+1)
+		switch it.task {
+		case 0:
+			rows = addStyle(rows, node.Dim)
+		case 1:
+			rows = addStyle(rows, node.Strikethrough)
+		}
+		nodes = append(nodes, prefixRows(rows, marker, node.Text(strings.Repeat(" ", markerW)))...)
+		if it.loose && i < len(b.items)-1 {
+			nodes = append(nodes, node.Text(""))
+		}
+	}
+	return nodes
+}
+
+// prefixRows puts first before the first row and rest before the
+// others, splicing into Rows so their spans stay direct children.
+func prefixRows(rows []node.Node, first, rest node.Node) []node.Node {
+	if len(rows) == 0 {
+		return []node.Node{node.Row(first)}
+	}
+	out := make([]node.Node, len(rows))
+	for i, r := range rows {
+		p := rest
+		if i == 0 {
+			p = first
+		}
+		if r.Type == node.RowNode {
+			r.Children = append([]node.Node{p}, r.Children...)
+			out[i] = r
+		} else {
+			out[i] = node.Row(p, r)
+		}
+	}
+	return out
+}
+
+// addStyle adds style to the text spans of line rows.
+func addStyle(rows []node.Node, style node.StyleFlags) []node.Node {
+	for _, r := range rows {
+		if r.Type != node.RowNode {
+			continue
+		}
+		for i := range r.Children {
+			if r.Children[i].Type == node.TextNode {
+				r.Children[i].Props.Style |= style
+			}
+		}
+	}
+	return rows
+}
+
+// wrapSpans breaks styled spans into lines of at most width cells,
+// breaking at spaces (or inside words longer than a line). Spans are
+// only split where a line breaks, so text that fits comes back as is.
+func wrapSpans(spans []node.Node, width int) [][]node.Node {
+	if width <= 0 {
+		return [][]node.Node{spans}
+	}
+	type piece struct {
+		span int
+		text string
+	}
+	var lines [][]piece
+	var cur []piece
+	curW := 0
+	breakLine := func() {
+		for len(cur) > 0 && strings.TrimSpace(cur[len(cur)-1].text) == "" {
+			cur = cur[:len(cur)-1]
+		}
+		lines = append(lines, cur)
+		cur, curW = nil, 0
+	}
+	for si, sp := range spans {
+		for _, word := range splitWords(sp.Props.Text) {
+			w := textwidth.String(word)
+			if strings.TrimSpace(word) == "" {
+				if curW > 0 || len(lines) == 0 {
+					cur = append(cur, piece{si, word})
+					curW += w
+				}
+				continue
+			}
+			if curW > 0 && curW+w > width {
+				breakLine()
+			}
+			// Hard-split words wider than a whole line.
+			for w > width {
+				head := fitPrefix(word, width)
+				if head == "" {
+					break
+				}
+				cur = append(cur, piece{si, head})
+				breakLine()
+				word = word[len(head):]
+				w = textwidth.String(word)
+			}
+			cur = append(cur, piece{si, word})
+			curW += w
+		}
+	}
+	lines = append(lines, cur)
+
+	// Rejoin neighbouring pieces of the same span.
+	out := make([][]node.Node, 0, len(lines))
+	for _, line := range lines {
+		var row []node.Node
+		prev := -1
+		for _, p := range line {
+			if p.span == prev {
+				row[len(row)-1].Props.Text += p.text
+				continue
+			}
+			n := spans[p.span]
+			n.Props.Text = p.text
+			row = append(row, n)
+			prev = p.span
+		}
+		out = append(out, row)
+	}
+	return out
+}
+
+// splitWords splits s into alternating runs of spaces and non-spaces.
+func splitWords(s string) []string {
+	var words []string
+	start := 0
+	for i := 1; i <= len(s); i++ {
+		if i == len(s) || (s[i] == ' ') != (s[start] == ' ') {
+			words = append(words, s[start:i])
+			start = i
+		}
+	}
+	return words
+}
+
+// fitPrefix returns the longest prefix of s at most w cells wide.
+func fitPrefix(s string, w int) string {
+	n := 0
+	for i, r := range s {
+		n += textwidth.Rune(r)
+		if n > w {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// renderTable renders a GFM table with columns sized to their widest
+// cell, shrinking the widest columns (and truncating their cells) when
+// the table is wider than width.
+func renderTable(b block, width int, colors ColorScheme) []node.Node {
+	header := make([][]node.Node, len(b.header))
+	for c, cell := range b.header {
+		header[c] = parseInline(cell, colors.Text, colors)
+		for i := range header[c] {
+			header[c][i].Props.Style |= node.Bold
+		}
+	}
+	rows := make([][][]node.Node, len(b.rows))
+	for r, row := range b.rows {
+		rows[r] = make([][]node.Node, len(row))
+		for c, cell := range row {
+			rows[r][c] = parseInline(cell, colors.Text, colors)
+		}
+	}
+
+	widths := make([]int, len(header))
+	for c := range header {
+		widths[c] = spansWidth(header[c])
+		for _, row := range rows {
+			widths[c] = max(widths[c], spansWidth(row[c]))
+		}
+	}
+	const gap = 3 // " │ "
+	total := gap * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	for width > 0 && total > width {
+		widest := 0
+		for c, w := range widths {
+			if w > widths[widest] {
+				widest = c
+			}
+		}
+		if widths[widest] <= 3 {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+
+	line := func(cells [][]node.Node) node.Node {
+		var spans []node.Node
+		for c, cell := range cells {
+			if c > 0 {
+				spans = append(spans, node.TextStyled(" │ ", colors.Rule, 0, 0))
+			}
+			cell = truncateSpans(cell, widths[c])
+			pad := widths[c] - spansWidth(cell)
+			left := 0
+			switch b.align[c] {
+			case alignRight:
+				left = pad
+			case alignCenter:
+				left = pad / 2
+			}
+			if left > 0 {
+				spans = append(spans, node.Text(strings.Repeat(" ", left)))
+			}
+			spans = append(spans, cell...)
+			if pad-left > 0 {
+				spans = append(spans, node.Text(strings.Repeat(" ", pad-left)))
+			}
+		}
+		return node.Row(spans...)
+	}
+
+	rules := make([]string, len(widths))
+	for c, w := range widths {
+		rules[c] = strings.Repeat("─", w)
+	}
+	nodes := []node.Node{line(header), node.TextStyled(strings.Join(rules, "─┼─"), colors.Rule, 0, 0)}
+	for _, row := range rows {
+		nodes = append(nodes, line(row))
+	}
+	return nodes
+}
+
+// spansWidth returns the display width of a run of spans.
+func spansWidth(spans []node.Node) int {
+	w := 0
+	for _, s := range spans {
+		w += textwidth.String(s.Props.Text)
+	}
+	return w
+}
+
+// truncateSpans cuts spans to at most w cells, ending in "…" when
+// anything was cut.
+func truncateSpans(spans []node.Node, w int) []node.Node {
+	if spansWidth(spans) <= w {
+		return spans
+	}
+	var out []node.Node
+	left := w
+	for _, s := range spans {
+		sw := textwidth.String(s.Props.Text)
+		if sw < left {
+			out = append(out, s)
+			left -= sw
+			continue
+		}
+		s.Props.Text = textwidth.Truncate(s.Props.Text+"…", left)
+		out = append(out, s)
+		break
+	}
+	return out
+}
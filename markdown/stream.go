@@ -0,0 +1,207 @@
+package markdown
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/stukennedy/tooey/node"
+)
+
+// Stream renders markdown incrementally as text arrives, e.g. the
+// tokens of an assistant reply read from an sse.Client. Append each
+// chunk and call Nodes from View.
+//
+// Blocks that later text can no longer change are rendered once and
+// cached; only the trailing block is parsed and rendered again on each
+// call. The trailing block is rendered tolerantly: an unterminated code
+// fence shows as an open code block, and unclosed emphasis or code
+// spans are closed so "**bo" already renders bold.
+//
+// Stream is a value: Append returns the updated stream, and earlier
+// values stay as they were. Copies share storage, so don't Append to
+// copies of one Stream from different goroutines.
+type Stream struct {
+	Width  int
+	Colors ColorScheme
+
+	// The text is kept as the pieces that settled, covering its first
+	// cut bytes, and the tail after them, so appending only copies the
+	// tail.
+	settled []string
+	tail    string
+	cut     int
+	cached  []node.Node // nodes for the settled text
+
+	// log is the storage settled and cached append into, shared with
+	// the values this one came from.
+	log *streamLog
+
+	// The Width and Colors cached was rendered with.
+	width  int
+	colors ColorScheme
+}
+
+// streamLog holds the longest settled and cached any copy of a stream
+// has appended. A value whose slices are as long owns the end and can
+// append in place; others must copy first.
+type streamLog struct {
+	settled []string
+	cached  []node.Node
+}
+
+// NewStream returns an empty stream rendering at width with colors.
+func NewStream(width int, colors ColorScheme) Stream {
+	return Stream{Width: width, Colors: colors, width: width, colors: colors}
+}
+
+// Text returns all text appended so far.
+func (s Stream) Text() string {
+	return strings.Join(s.settled, "") + s.tail
+}
+
+// Append adds text to the stream and caches any blocks it completes.
+// Changing Width or Colors drops the cache on the next Append.
+func (s Stream) Append(text string) Stream {
+	s.tail += text
+	if s.Width != s.width || s.Colors != s.colors {
+		s.tail = s.Text()
+		s.settled, s.cut, s.cached, s.log = nil, 0, nil, nil
+		s.width, s.colors = s.Width, s.Colors
+	} else if strings.IndexByte(text, '\n') < 0 {
+		// Only whole lines settle blocks: a partial "2" could still
+		// become "2. item" and continue a list above it.
+		return s
+	}
+
+	tail := s.tail
+	end := strings.LastIndexByte(tail, '\n')
+	if end < 0 {
+		return s
+	}
+	raw := strings.Split(tail[:end], "\n")
+	lines := splitLines(tail[:end])
+
+	// Everything before the last non-blank block is settled: that
+	// block's first line already decides where its predecessors end.
+	var blocks []block
+	var starts []int
+	last := -1
+	for i := 0; i < len(lines); {
+		b, next := parseBlock(lines, i)
+		if b.kind != blankBlock {
+			last = len(blocks)
+		}
+		blocks = append(blocks, b)
+		starts = append(starts, i)
+		i = next
+	}
+	if last <= 0 {
+		return s
+	}
+	n := 0
+	for _, l := range raw[:starts[last]] {
+		n += len(l) + 1
+	}
+	done := renderBlocks(blocks[:last], s.width, s.colors, 0)
+	if s.log == nil || len(s.log.cached) != len(s.cached) || len(s.log.settled) != len(s.settled) {
+		// Another value already appended past this one; branch off so
+		// it keeps its own cache.
+		s.log = &streamLog{settled: slices.Clone(s.settled), cached: slices.Clone(s.cached)}
+	}
+	s.log.cached = append(s.log.cached, done...)
+	s.log.settled = append(s.log.settled, tail[:n])
+	s.cached, s.settled = s.log.cached, s.log.settled
+	s.tail = tail[n:]
+	s.cut += n
+	return s
+}
+
+// Nodes returns the rendered stream, one node per visual line as from
+// RenderWithColors.
+func (s Stream) Nodes() []node.Node {
+	cached, tail := s.cached, s.tail
+	if s.Width != s.width || s.Colors != s.colors {
+		cached, tail = nil, s.Text()
+	}
+	blocks := parseBlocks(splitLines(tail))
+	closeTrailing(blocks)
+	nodes := make([]node.Node, 0, len(cached)+len(blocks))
+	nodes = append(nodes, cached...)
+	return append(nodes, renderBlocks(blocks, s.Width, s.Colors, 0)...)
+}
+
+// closeTrailing closes open inline markup in the last paragraph or
+// heading, descending into the last list item or quote.
+func closeTrailing(blocks []block) {
+	for i := len(blocks) - 1; i >= 0; i-- {
+		b := &blocks[i]
+		switch b.kind {
+		case blankBlock:
+			continue
+		case paragraphBlock, headingBlock:
+			b.text = closeInline(b.text)
+		case quoteBlock:
+			closeTrailing(b.children)
+		case listBlock:
+			if len(b.items) > 0 {
+				closeTrailing(b.items[len(b.items)-1].blocks)
+			}
+		}
+		return
+	}
+}
+
+// closeInline appends closers for emphasis and code spans left open at
+// the end of streaming text. An opener with nothing after it yet is
+// dropped instead, so a lone "**" doesn't flash as literal stars.
+func closeInline(text string) string {
+	type opener struct {
+		marker string
+		at     int
+	}
+	var open []opener
+	for i := 0; i < len(text); {
+		if text[i] == '`' {
+			end := strings.IndexByte(text[i+1:], '`')
+			if end < 0 {
+				open = append(open, opener{"`", i})
+				break
+			}
+			i += end + 2
+			continue
+		}
+		m := ""
+		for _, marker := range []string{"***", "**", "*", "~~"} {
+			if strings.HasPrefix(text[i:], marker) {
+				m = marker
+				break
+			}
+		}
+		if m == "" {
+			i++
+			continue
+		}
+		switch {
+		case len(open) > 0 && open[len(open)-1].marker == m:
+			open = open[:len(open)-1]
+		case i+len(m) == len(text) || text[i+len(m)] != ' ':
+			// Like CommonMark, an opener must not be followed by a space.
+			open = append(open, opener{m, i})
+		}
+		i += len(m)
+	}
+	for k := len(open) - 1; k >= 0; k-- {
+		o := open[k]
+		if o.at+len(o.marker) == len(text) {
+			text = text[:o.at]
+			continue
+		}
+		if r, _ := utf8.DecodeLastRuneInString(text); r == ' ' && o.marker != "`" {
+			// "**bold " closes as "**bold**", not "**bold **".
+			text = strings.TrimRight(text, " ")
+		}
+		text += o.marker
+	}
+	return text
+}
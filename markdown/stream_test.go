@@ -0,0 +1,132 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stukennedy/tooey/node"
+	"github.com/stukennedy/tooey/tooeytest"
+)
+
+func streamFrame(s Stream, height int) string {
+	return tooeytest.RenderText(node.Column(s.Nodes()...), s.Width, height)
+}
+
+func TestStreamMatchesRender(t *testing.T) {
+	src := "# Title\n\nSome **bold** text that wraps\nacross lines.\n\n" +
+		"- one\n- two\n\n  still two\n\n1. a\n2. b\n\n" +
+		"```go\nfunc main() {}\n```\n\n> quoted\n> text\n\n| a | b |\n|---|---|\n| 1 | 2 |\n\nend"
+	s := NewStream(20, DefaultColors(7))
+	for _, r := range src {
+		s = s.Append(string(r))
+	}
+	got := streamFrame(s, 40)
+	want := tooeytest.RenderText(node.Column(Render(src, 20, 7)...), 20, 40)
+	if got != want {
+		t.Fatalf("stream:\n%s\nwant:\n%s", got, want)
+	}
+	if s.cut == 0 || len(s.cached) == 0 {
+		t.Error("expected completed blocks to be cached")
+	}
+	// Everything up to the table, the last block with whole lines, settles.
+	if rest := src[s.cut:]; !strings.HasPrefix(rest, "| a | b |") {
+		t.Errorf("cache stopped before %q", rest)
+	}
+}
+
+func TestStreamCachesCompletedBlocks(t *testing.T) {
+	s := NewStream(20, DefaultColors(7)).Append("first para\n\nsecond")
+	if s.Text() != "first para\n\nsecond" {
+		t.Errorf("Text = %q", s.Text())
+	}
+	if s.cut != 0 {
+		t.Fatal("cached before the next block's first line was complete")
+	}
+	s = s.Append("\n")
+	if len(s.cached) != 2 || s.cut != len("first para\n\n") {
+		t.Fatalf("cached %d nodes up to %d", len(s.cached), s.cut)
+	}
+
+	// Earlier values keep their own cache.
+	t1 := s.Append("para\n\nthird\n")
+	t2 := s.Append("\nother\n")
+	if got := streamFrame(t1, 5); got != "first para\n\nsecond para\n\nthird" {
+		t.Errorf("t1:\n%s", got)
+	}
+	if got := streamFrame(t2, 5); got != "first para\n\nsecond\n\nother" {
+		t.Errorf("t2:\n%s", got)
+	}
+
+	// A width change re-renders everything.
+	t1.Width = 6
+	if got := streamFrame(t1, 8); got != "first\npara\n\nsecond\npara\n\nthird" {
+		t.Errorf("resized:\n%s", got)
+	}
+	t1 = t1.Append("!")
+	if t1.width != 6 || t1.cut != len("first para\n\nsecond\npara\n\n") {
+		t.Errorf("cache not rebuilt: width %d, cut %d", t1.width, t1.cut)
+	}
+}
+
+func TestStreamPartialLineDoesNotSettle(t *testing.T) {
+	s := NewStream(20, DefaultColors(7)).Append("1. a\n\n2")
+	if s.cut != 0 {
+		t.Fatalf("list cached before its next line was complete")
+	}
+	s = s.Append(". b\n")
+	want := "  1. a\n\n  2. b"
+	if got := streamFrame(s, 4); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestStreamOpenFence(t *testing.T) {
+	s := NewStream(20, DefaultColors(7)).Append("intro\n\n```go\nx := 1\n")
+	nodes := s.Nodes()
+	last := nodes[len(nodes)-1]
+	if last.Type != node.BoxNode {
+		t.Fatalf("open fence rendered as %v, want a code box", last.Type)
+	}
+	want := "intro\n\n╭──────────────────╮\n│x := 1            │\n│                  │\n╰──────────────────╯"
+	if got := streamFrame(s, 6); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestStreamClosesInline(t *testing.T) {
+	nodes := NewStream(20, DefaultColors(7)).Append("some **bo").Nodes()
+	spans := nodes[0].Children
+	if len(spans) != 2 || spans[1].Props.Text != "bo" || spans[1].Props.Style&node.Bold == 0 {
+		t.Errorf("spans = %+v", spans)
+	}
+
+	for in, want := range map[string]string{
+		"plain":           "plain",
+		"a **b":           "a **b**",
+		"a **b ":          "a **b**",
+		"a **":            "a ",
+		"*a **b":          "*a **b***",
+		"***a":            "***a***",
+		"2 * 3":           "2 * 3",
+		"run `go te":      "run `go te`",
+		"~~gone":          "~~gone~~",
+		"`**` and *done*": "`**` and *done*",
+	} {
+		if got := closeInline(in); got != want {
+			t.Errorf("closeInline(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func BenchmarkStreamAppend(b *testing.B) {
+	// A long reply arriving a few bytes at a time: each Append costs
+	// the unsettled tail, not the whole reply.
+	para := "Some **streamed** text with `code` in it, arriving token by token.\n\n"
+	src := strings.Repeat(para, 200)
+	for b.Loop() {
+		s := NewStream(80, DefaultColors(7))
+		for i := 0; i < len(src); i += 4 {
+			s = s.Append(src[i:min(i+4, len(src))])
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package node
+
+import "github.com/stukennedy/tooey/textwidth"
+
+// CanvasMode selects how a Canvas maps pixels onto terminal cells.
+type CanvasMode int
+
+const (
+	// CanvasBraille gives each cell 2x4 dots. A cell has one color:
+	// the last one drawn into it.
+	CanvasBraille CanvasMode = iota
+	// CanvasHalfBlock gives each cell two stacked pixels, each with its
+	// own color (drawn as the FG and BG of "▀"/"▄").
+	CanvasHalfBlock
+)
+
+// Canvas creates a drawing surface. Like Spacer it flexes to fill the
+// space it is given (cap it with WithSize). At paint time draw is called
+// with a Surface sized to the node's resolved rect; whatever it draws is
+// painted within the node's clip. Cells left empty show the node's BG.
+func Canvas(mode CanvasMode, draw func(*Surface)) Node {
+	return Node{Type: CanvasNode, Props: Props{FlexWeight: 1, CanvasMode: mode, Draw: draw}}
+}
+
+// Surface is the pixel grid handed to a Canvas draw callback. All
+// coordinates are in pixels, with (0, 0) at the top left; drawing
+// outside the surface is ignored.
+type Surface struct {
+	Mode CanvasMode
+	// W and H are the size in pixels; Cols and Rows the size in cells.
+	W, H       int
+	Cols, Rows int
+
+	cells []surfaceCell
+}
+
+// surfaceCell holds one terminal cell of a Surface. For Braille, bits
+// are the dot pattern and fg its color; for half-blocks, bit 1 is the
+// top pixel (colored fg) and bit 2 the bottom (colored bg). A text rune
+// overrides the pixels.
+type surfaceCell struct {
+	bits   uint8
+	fg, bg Color
+	text   rune
+	cont   bool // right half of a wide text rune
+}
+
+// NewSurface returns an empty surface covering cols x rows cells.
+func NewSurface(mode CanvasMode, cols, rows int) *Surface {
+	cols, rows = max(cols, 0), max(rows, 0)
+	s := &Surface{Mode: mode, Cols: cols, Rows: rows, cells: make([]surfaceCell, cols*rows)}
+	cw, ch := s.cellSize()
+	s.W, s.H = cols*cw, rows*ch
+	return s
+}
+
+// cellSize returns how many pixels wide and tall one cell is.
+func (s *Surface) cellSize() (int, int) {
+	if s.Mode == CanvasHalfBlock {
+		return 1, 2
+	}
+	return 2, 4
+}
+
+// cellAt returns the cell holding pixel (x, y), or nil if it is off
+// the surface.
+func (s *Surface) cellAt(x, y int) *surfaceCell {
+	if x < 0 || y < 0 || x >= s.W || y >= s.H {
+		return nil
+	}
+	cw, ch := s.cellSize()
+	return &s.cells[(y/ch)*s.Cols+x/cw]
+}
+
+// Set turns on pixel (x, y) in color c.
+func (s *Surface) Set(x, y int, c Color) {
+	sc := s.cellAt(x, y)
+	if sc == nil {
+		return
+	}
+	if s.Mode == CanvasHalfBlock {
+		if y%2 == 0 {
+			sc.bits |= 1
+			sc.fg = c
+		} else {
+			sc.bits |= 2
+			sc.bg = c
+		}
+		return
+	}
+	sc.bits |= brailleBit(x%2, y%4)
+	sc.fg = c
+}
+
+// Unset turns off pixel (x, y).
+func (s *Surface) Unset(x, y int) {
+	sc := s.cellAt(x, y)
+	if sc == nil {
+		return
+	}
+	switch {
+	case s.Mode != CanvasHalfBlock:
+		sc.bits &^= brailleBit(x%2, y%4)
+	case y%2 == 0:
+		sc.bits &^= 1
+	default:
+		sc.bits &^= 2
+	}
+}
+
+// brailleBit returns the dot bit for column dx (0-1) and row dy (0-3)
+// of a Braille cell.
+func brailleBit(dx, dy int) uint8 {
+	if dy == 3 {
+		return 0x40 << dx
+	}
+	return 1 << (dy + 3*dx)
+}
+
+// Line draws a line from (x0, y0) to (x1, y1).
+func (s *Surface) Line(x0, y0, x1, y1 int, c Color) {
+	dx, dy := x1-x0, y1-y0
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy > 0 {
+		dy = -dy
+	}
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	// Bresenham's algorithm.
+	e := dx + dy
+	for {
+		s.Set(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		if e2 := 2 * e; e2 >= dy {
+			e += dy
+			x0 += sx
+		} else {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+// Rect draws the outline of a w x h rectangle at (x, y).
+func (s *Surface) Rect(x, y, w, h int, c Color) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	s.Line(x, y, x+w-1, y, c)
+	s.Line(x, y+h-1, x+w-1, y+h-1, c)
+	s.Line(x, y, x, y+h-1, c)
+	s.Line(x+w-1, y, x+w-1, y+h-1, c)
+}
+
+// FillRect fills a w x h rectangle at (x, y).
+func (s *Surface) FillRect(x, y, w, h int, c Color) {
+	for py := y; py < y+h; py++ {
+		for px := x; px < x+w; px++ {
+			s.Set(px, py, c)
+		}
+	}
+}
+
+// Circle draws the outline of a circle of radius r centred on (cx, cy).
+// Braille dots are not square, so circles there look slightly tall.
+func (s *Surface) Circle(cx, cy, r int, c Color) {
+	if r < 0 {
+		return
+	}
+	// Midpoint circle algorithm, plotting all eight octants.
+	x, y, e := r, 0, 1-r
+	for x >= y {
+		for _, p := range [8][2]int{{x, y}, {y, x}, {-y, x}, {-x, y}, {-x, -y}, {-y, -x}, {y, -x}, {x, -y}} {
+			s.Set(cx+p[0], cy+p[1], c)
+		}
+		y++
+		if e < 0 {
+			e += 2*y + 1
+		} else {
+			x--
+			e += 2*(y-x) + 1
+		}
+	}
+}
+
+// Text writes a label starting in the cell that holds pixel (x, y). Text
+// replaces any pixels in the cells it covers and is clipped at the
+// right edge.
+func (s *Surface) Text(x, y int, text string, fg Color) {
+	if s.cellAt(x, y) == nil {
+		return
+	}
+	cw, ch := s.cellSize()
+	col, row := x/cw, y/ch
+	for _, r := range text {
+		w := textwidth.Rune(r)
+		if w == 0 {
+			continue
+		}
+		if col+w > s.Cols {
+			return
+		}
+		s.cells[row*s.Cols+col] = surfaceCell{text: r, fg: fg}
+		if w == 2 {
+			s.cells[row*s.Cols+col+1] = surfaceCell{cont: true}
+		}
+		col += w
+	}
+}
+
+// Cell returns what to show in cell (col, row): the rune and its
+// colors. A zero rune means there is nothing to paint, either because
+// the cell is empty or because a wide text rune to its left covers it.
+func (s *Surface) Cell(col, row int) (r rune, fg, bg Color) {
+	if col < 0 || row < 0 || col >= s.Cols || row >= s.Rows {
+		return 0, 0, 0
+	}
+	sc := s.cells[row*s.Cols+col]
+	switch {
+	case sc.cont, sc.text == 0 && sc.bits == 0:
+		return 0, 0, 0
+	case sc.text != 0:
+		return sc.text, sc.fg, 0
+	case s.Mode != CanvasHalfBlock:
+		return 0x2800 + rune(sc.bits), sc.fg, 0
+	case sc.bits == 1:
+		return '▀', sc.fg, 0
+	case sc.bits == 2:
+		return '▄', sc.bg, 0
+	case sc.fg == sc.bg:
+		return '█', sc.fg, 0
+	}
+	return '▀', sc.fg, sc.bg
+}
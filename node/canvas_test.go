@@ -0,0 +1,93 @@
+package node
+
+import "testing"
+
+// cellText returns a surface row as a string, with empty cells as
+// spaces.
+func cellText(s *Surface, row int) string {
+	out := make([]rune, 0, s.Cols)
+	for col := range s.Cols {
+		r, _, _ := s.Cell(col, row)
+		if r == 0 {
+			r = ' '
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+func TestSurfaceBrailleDots(t *testing.T) {
+	s := NewSurface(CanvasBraille, 2, 1)
+	if s.W != 4 || s.H != 4 {
+		t.Fatalf("pixel size = %dx%d, want 4x4", s.W, s.H)
+	}
+	s.Set(0, 0, 1)
+	s.Set(1, 3, 2)
+	s.Set(4, 0, 3) // off the surface
+	if got := cellText(s, 0); got != "⢁ " {
+		t.Fatalf("dots = %q", got)
+	}
+	if _, fg, _ := s.Cell(0, 0); fg != 2 {
+		t.Errorf("cell color = %v, want the last drawn (2)", fg)
+	}
+	s.Unset(0, 0)
+	s.Unset(1, 3)
+	if r, _, _ := s.Cell(0, 0); r != 0 {
+		t.Errorf("unset cell = %q, want empty", r)
+	}
+}
+
+func TestSurfaceShapes(t *testing.T) {
+	s := NewSurface(CanvasBraille, 3, 1)
+	s.Line(0, 0, 5, 0, 0)
+	if got := cellText(s, 0); got != "⠉⠉⠉" {
+		t.Fatalf("line = %q", got)
+	}
+
+	s = NewSurface(CanvasBraille, 2, 1)
+	s.Rect(0, 0, 4, 4, 0)
+	if got := cellText(s, 0); got != "⣏⣹" {
+		t.Fatalf("rect = %q", got)
+	}
+
+	s = NewSurface(CanvasHalfBlock, 3, 2)
+	s.Circle(1, 1, 1, 0)
+	want := []string{"▄▀▄", " ▀ "}
+	for row, w := range want {
+		if got := cellText(s, row); got != w {
+			t.Errorf("circle row %d = %q, want %q", row, got, w)
+		}
+	}
+}
+
+func TestSurfaceHalfBlockColors(t *testing.T) {
+	s := NewSurface(CanvasHalfBlock, 3, 1)
+	s.Set(0, 0, 1)
+	s.Set(1, 1, 2)
+	s.Set(2, 0, 3)
+	s.Set(2, 1, 4)
+	s.FillRect(3, 0, 1, 2, 5) // off the surface
+
+	if r, fg, _ := s.Cell(0, 0); r != '▀' || fg != 1 {
+		t.Errorf("top pixel = %q fg %v", r, fg)
+	}
+	if r, fg, _ := s.Cell(1, 0); r != '▄' || fg != 2 {
+		t.Errorf("bottom pixel = %q fg %v", r, fg)
+	}
+	if r, fg, bg := s.Cell(2, 0); r != '▀' || fg != 3 || bg != 4 {
+		t.Errorf("two-color cell = %q fg %v bg %v", r, fg, bg)
+	}
+}
+
+func TestSurfaceText(t *testing.T) {
+	s := NewSurface(CanvasBraille, 4, 2)
+	s.FillRect(0, 0, s.W, s.H, 0)
+	s.Text(2, 4, "a世b", 7)
+	// "b" no longer fits; the wide rune's right half paints nothing.
+	if got := cellText(s, 1); got != "⣿a世 " {
+		t.Fatalf("label row = %q", got)
+	}
+	if r, fg, _ := s.Cell(1, 1); r != 'a' || fg != 7 {
+		t.Errorf("label cell = %q fg %v", r, fg)
+	}
+}
@@ -18,6 +18,8 @@ const (
 	PaneNode
 	SpacerNode
 	OverlayNode
+	CanvasNode
+	RuleNode
 )
 
 // Color represents a terminal color. The zero value is the terminal default.
@@ -72,6 +74,49 @@ func (c Color) Ansi256() uint8 {
 	return uint8(c)
 }
 
+// Ansi16 returns the nearest of the 16 standard ANSI colors (0-7
+// normal, 8-15 bright), for terminals without the 256-color palette.
+func (c Color) Ansi16() uint8 {
+	if !c.IsRGB() && c.Ansi256() < 16 {
+		return c.Ansi256()
+	}
+	r, g, b := c.RGBValues()
+	if !c.IsRGB() {
+		r, g, b = paletteRGB(c.Ansi256())
+	}
+	best, bestDist := 0, -1
+	for i, p := range ansi16RGB {
+		dr, dg, db := int(r)-int(p[0]), int(g)-int(p[1]), int(b)-int(p[2])
+		if d := dr*dr + dg*dg + db*db; bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return uint8(best)
+}
+
+// ansi16RGB are the xterm defaults for the 16 standard colors.
+var ansi16RGB = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// paletteRGB returns the RGB value of a 256-palette entry.
+func paletteRGB(n uint8) (r, g, b uint8) {
+	switch {
+	case n < 16:
+		p := ansi16RGB[n]
+		return p[0], p[1], p[2]
+	case n >= 232:
+		v := 8 + 10*(n-232)
+		return v, v, v
+	}
+	levels := [6]uint8{0, 95, 135, 175, 215, 255}
+	n -= 16
+	return levels[n/36], levels[n/6%6], levels[n%6]
+}
+
 // rgbToAnsi256 maps 24-bit RGB to the nearest xterm-256 palette entry
 // (6x6x6 color cube at 16..231, grayscale ramp at 232..255).
 func rgbToAnsi256(r, g, b uint8) uint8 {
@@ -97,7 +142,7 @@ func rgbToAnsi256(r, g, b uint8) uint8 {
 }
 
 // StyleFlags are bitwise text style attributes.
-type StyleFlags uint8
+type StyleFlags uint16
 
 const (
 	Bold      StyleFlags = 1 << iota
@@ -105,6 +150,18 @@ const (
 	Italic
 	Underline
 	Reverse
+	Strikethrough
+	Blink
+	Overline
+	Hidden
+
+	// Underline variants (SGR 4:2 to 4:5). Terminals without styled
+	// underlines show a plain underline. If several are set, the first
+	// listed wins.
+	DoubleUnderline
+	CurlyUnderline
+	DottedUnderline
+	DashedUnderline
 )
 
 // BorderStyle defines box border appearance.
@@ -129,6 +186,8 @@ type Props struct {
 	FG           Color
 	BG           Color
 	Style        StyleFlags
+	// UnderlineColor colors underlines (SGR 58); default is the FG.
+	UnderlineColor Color
 	ScrollOffset   int  // vertical scroll offset for Column/List/Pane
 	ScrollToBottom bool // auto-scroll so bottom content is visible
 
@@ -144,6 +203,27 @@ type Props struct {
 	// topmost scope. Opening a scope saves the current focus; removing
 	// it restores it. Give scopes a Key so nested scopes stay stable.
 	FocusScope bool
+
+	// PassThrough makes this subtree invisible to the mouse and to
+	// focus: hit tests skip it (clicks reach whatever is beneath) and
+	// its focusables are never focused. Use for non-interactive overlay
+	// layers such as notifications.
+	PassThrough bool
+
+	// Link makes a Text node's cells a hyperlink to this URL. Terminals
+	// that support OSC 8 make them Ctrl+clickable; others ignore it.
+	Link string
+
+	// CanvasMode and Draw configure a Canvas node; see Canvas.
+	CanvasMode CanvasMode
+	Draw       func(*Surface)
+
+	// Memo is a cache key for this subtree. When a layout.Cache and
+	// cell.PaintCache see the same key at the same size as in the last
+	// frame, they reuse that frame's layout and painted cells instead of
+	// redoing them. The key must change whenever anything in the subtree
+	// does, e.g. by including a version or content hash.
+	Memo string
 }
 
 // Node represents a virtual UI element in the component tree.
@@ -187,6 +267,19 @@ func Spacer() Node {
 	return Node{Type: SpacerNode, Props: Props{FlexWeight: 1}}
 }
 
+// HRule is a horizontal line one cell tall that spans the width layout
+// gives it. Like box borders it is drawn with "─", or "-" when
+// cell.SetASCIIBorders is on.
+func HRule() Node {
+	return Node{Type: RuleNode, Props: Props{Text: "─", Height: 1}}
+}
+
+// VRule is a vertical line one cell wide that spans the height layout
+// gives it, drawn with "│" (or "|").
+func VRule() Node {
+	return Node{Type: RuleNode, Props: Props{Text: "│", Width: 1}}
+}
+
 // Overlay stacks children in the same rect: the first child is the base
 // layer, later children paint on top of it. Use for modals, popups, and
 // dropdowns. Compose with Centered (or Spacers) to position a layer.
@@ -248,6 +341,12 @@ func (n Node) WithStyle(s StyleFlags) Node {
 	return n
 }
 
+// WithUnderlineColor sets the underline color and returns the node.
+func (n Node) WithUnderlineColor(c Color) Node {
+	n.Props.UnderlineColor = c
+	return n
+}
+
 // WithScrollOffset sets the vertical scroll offset.
 func (n Node) WithScrollOffset(offset int) Node {
 	n.Props.ScrollOffset = offset
@@ -288,6 +387,27 @@ func (n Node) WithFocusScope() Node {
 	return n
 }
 
+// WithPassThrough lets clicks and focus pass through this subtree to
+// the layers beneath (e.g. a notification overlay). See
+// Props.PassThrough.
+func (n Node) WithPassThrough() Node {
+	n.Props.PassThrough = true
+	return n
+}
+
+// WithLink makes the text a hyperlink to url. See Props.Link.
+func (n Node) WithLink(url string) Node {
+	n.Props.Link = url
+	return n
+}
+
+// WithMemo marks the subtree for reuse across frames under key. See
+// Props.Memo.
+func (n Node) WithMemo(key string) Node {
+	n.Props.Memo = key
+	return n
+}
+
 // Bar creates a full-width text node with background color fill.
 // Use in a Row; the FlexWeight=1 causes it to stretch to fill available width.
 func Bar(text string, fg, bg Color, style StyleFlags) Node {
@@ -0,0 +1,173 @@
+package termcap
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"time"
+)
+
+// queries asks, in order: the terminal's name and version (XTVERSION),
+// whether it supports synchronized output (DECRQM for mode 2026), its
+// kitty keyboard flags, and its primary device attributes (DA1). Every
+// terminal answers DA1 and replies arrive in order, so the DA1 reply
+// marks the end of the answers.
+const queries = "\x1b[>0q" + "\x1b[?2026$p" + "\x1b[?u" + "\x1b[c"
+
+// Query refines c by asking the terminal about itself: it writes the
+// queries to w and reads the replies from r, waiting at most timeout
+// for a terminal that doesn't answer. r must be a terminal in raw mode
+// (or the replies are echoed and line-buffered).
+//
+// Query may read past the replies, e.g. keys typed meanwhile, so it
+// returns the reader to use for further input in place of r.
+func Query(c Caps, r io.Reader, w io.Writer, timeout time.Duration) (Caps, io.Reader) {
+	in := newChunkReader(r)
+	if _, err := io.WriteString(w, queries); err != nil {
+		return c, in
+	}
+	deadline := time.After(timeout)
+	var buf []byte
+	for {
+		select {
+		case ch, ok := <-in.ch:
+			if !ok {
+				in.buf = buf
+				return c, in
+			}
+			buf = append(buf, ch.data...)
+			var done bool
+			c, buf, done = parseReplies(c, buf)
+			if done || ch.err != nil {
+				in.buf, in.err = buf, ch.err
+				return c, in
+			}
+		case <-deadline:
+			in.buf = buf
+			return c, in
+		}
+	}
+}
+
+// parseReplies applies the query replies found in buf to c and returns
+// the remaining input with the replies removed. done reports that the
+// DA1 reply, the last one, has arrived; until then a sequence cut off
+// at the end of buf is kept whole for the next read.
+func parseReplies(c Caps, buf []byte) (_ Caps, rest []byte, done bool) {
+	for i := 0; i < len(buf); {
+		if buf[i] != 0x1b {
+			rest = append(rest, buf[i])
+			i++
+			continue
+		}
+		n, complete := replyLen(buf[i:])
+		if !complete {
+			return c, append(rest, buf[i:]...), false
+		}
+		if n == 0 {
+			rest = append(rest, buf[i])
+			i++
+			continue
+		}
+		seq := buf[i : i+n]
+		i += n
+		switch {
+		case seq[1] == 'P':
+			// DCS > | name ST
+			name := bytes.TrimSuffix(bytes.TrimSuffix(seq[4:], []byte("\x1b\\")), []byte("\a"))
+			c = c.withTerminal(string(name))
+		case bytes.HasSuffix(seq, []byte("$y")):
+			// CSI ? 2026 ; Ps $ y: 1 (set) or 2 (reset) mean supported.
+			params := bytes.Split(seq[3:n-2], []byte(";"))
+			if len(params) == 2 && string(params[0]) == "2026" {
+				ps, _ := strconv.Atoi(string(params[1]))
+				c.SyncOutput = ps == 1 || ps == 2
+			}
+		case seq[n-1] == 'u':
+			c.KittyKeyboard = true
+		case seq[n-1] == 'c':
+			return c, append(rest, buf[i:]...), true
+		}
+	}
+	return c, rest, false
+}
+
+// replyLen returns the length of the query reply starting at s (which
+// begins with ESC), 0 if s doesn't start one, and whether s holds the
+// whole sequence.
+func replyLen(s []byte) (int, bool) {
+	if len(s) < 3 {
+		// Too short to tell, unless the second byte rules a reply out.
+		return 0, len(s) == 2 && s[1] != 'P' && s[1] != '['
+	}
+	switch {
+	case s[1] == 'P' && s[2] == '>':
+		for j := 3; j < len(s); j++ {
+			if s[j] == '\a' {
+				return j + 1, true
+			}
+			if s[j] == 0x1b && j+1 < len(s) && s[j+1] == '\\' {
+				return j + 2, true
+			}
+		}
+		return 0, false
+	case s[1] == '[' && s[2] == '?':
+		for j := 3; j < len(s); j++ {
+			b := s[j]
+			if b >= 0x40 && b <= 0x7e {
+				if b == 'c' || b == 'u' || (b == 'y' && s[j-1] == '$') {
+					return j + 1, true
+				}
+				return 0, true // some other CSI ? sequence
+			}
+		}
+		return 0, false
+	}
+	return 0, true
+}
+
+// chunkReader hands out the data read by a background goroutine, which
+// lets Query give up waiting without losing what arrives later.
+type chunkReader struct {
+	ch  chan chunk
+	buf []byte
+	err error
+}
+
+type chunk struct {
+	data []byte
+	err  error
+}
+
+func newChunkReader(r io.Reader) *chunkReader {
+	cr := &chunkReader{ch: make(chan chunk, 4)}
+	go func() {
+		defer close(cr.ch)
+		for {
+			b := make([]byte, 4096)
+			n, err := r.Read(b)
+			cr.ch <- chunk{data: b[:n], err: err}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return cr
+}
+
+// Read implements io.Reader.
+func (cr *chunkReader) Read(p []byte) (int, error) {
+	for len(cr.buf) == 0 {
+		if cr.err != nil {
+			return 0, cr.err
+		}
+		ch, ok := <-cr.ch
+		if !ok {
+			return 0, io.EOF
+		}
+		cr.buf, cr.err = ch.data, ch.err
+	}
+	n := copy(p, cr.buf)
+	cr.buf = cr.buf[n:]
+	return n, nil
+}
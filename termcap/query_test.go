@@ -0,0 +1,85 @@
+package termcap
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+// fakeTerminal records what is written to it and returns one queued
+// reply per read.
+type fakeTerminal struct {
+	written bytes.Buffer
+	replies chan []byte
+}
+
+func (f *fakeTerminal) Write(p []byte) (int, error) { return f.written.Write(p) }
+
+func (f *fakeTerminal) Read(p []byte) (int, error) {
+	b, ok := <-f.replies
+	if !ok {
+		return 0, io.EOF
+	}
+	return copy(p, b), nil
+}
+
+func TestQuery(t *testing.T) {
+	term := &fakeTerminal{replies: make(chan []byte, 8)}
+	for _, part := range []string{
+		"\x1bP>|kitty(0.35.2)\x1b\\\x1b[?2026;2",
+		"$y\x1b[?0u",
+		"\x1b[?62;22cq", // DA1, then a key typed meanwhile
+		"more",
+	} {
+		term.replies <- []byte(part)
+	}
+	close(term.replies)
+
+	c, in := Query(FromEnv(env(map[string]string{"TERM": "xterm-256color"})), term, term, time.Second)
+	if term.written.String() != queries {
+		t.Errorf("wrote %q", term.written.String())
+	}
+	if c.Terminal != "kitty(0.35.2)" || c.Colors != TrueColor || !c.SyncOutput || !c.KittyKeyboard {
+		t.Errorf("caps = %+v", c)
+	}
+	rest, err := io.ReadAll(in)
+	if err != nil || string(rest) != "qmore" {
+		t.Errorf("remaining input = %q, %v", rest, err)
+	}
+}
+
+func TestQueryTimeout(t *testing.T) {
+	r, w := io.Pipe()
+	base := FromEnv(env(map[string]string{"TERM": "xterm-256color"}))
+	start := time.Now()
+	c, in := Query(base, r, io.Discard, 20*time.Millisecond)
+	if time.Since(start) > time.Second {
+		t.Fatal("Query did not time out")
+	}
+	if c != base {
+		t.Errorf("caps changed without replies: %+v", c)
+	}
+
+	// Input arriving after the timeout still reaches the caller, keys
+	// and all.
+	go func() {
+		w.Write([]byte("\x1b[Ax"))
+		w.Close()
+	}()
+	rest, _ := io.ReadAll(in)
+	if string(rest) != "\x1b[Ax" {
+		t.Errorf("late input = %q", rest)
+	}
+}
+
+func TestParseRepliesKeepsOtherInput(t *testing.T) {
+	c, rest, done := parseReplies(Caps{}, []byte("a\x1b[Ab\x1b[?2026;0$y\x1b[?1;2"))
+	if done || c.SyncOutput {
+		t.Errorf("done %v, caps %+v", done, c)
+	}
+	// The cut-off DA1 reply is held back whole.
+	if string(rest) != "a\x1b[Ab\x1b[?1;2" {
+		t.Errorf("rest = %q", rest)
+	}
+}
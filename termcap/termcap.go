@@ -0,0 +1,159 @@
+// Package termcap detects what the terminal can display: color depth,
+// Unicode line drawing, synchronized output and keyboard protocol
+// support. Detection starts from the environment (TERM, NO_COLOR,
+// COLORTERM and the locale), is refined from the terminfo database, and
+// can be completed by querying the terminal itself (see Query).
+//
+// Renderers degrade to what is detected: ansi.SetColorLevel maps colors
+// down to 16 colors or none, and cell.SetASCIIBorders draws boxes with
+// ASCII. app.Run applies both from Detect at startup, keeping a color
+// depth the program set explicitly (see ansi.SetDetectedColorLevel).
+package termcap
+
+import (
+	"os"
+	"strings"
+)
+
+// ColorLevel is the color depth a terminal supports.
+type ColorLevel int
+
+const (
+	Mono      ColorLevel = iota // no color (NO_COLOR, dumb terminals)
+	ANSI16                      // the 16 standard colors
+	ANSI256                     // the xterm 256-color palette
+	TrueColor                   // 24-bit RGB
+)
+
+// String returns the level's name, e.g. "256".
+func (l ColorLevel) String() string {
+	switch l {
+	case Mono:
+		return "mono"
+	case ANSI16:
+		return "16"
+	case ANSI256:
+		return "256"
+	case TrueColor:
+		return "truecolor"
+	}
+	return "unknown"
+}
+
+// Caps describes the capabilities of a terminal.
+type Caps struct {
+	Colors ColorLevel
+
+	// Unicode reports whether box drawing and other non-ASCII glyphs
+	// display correctly.
+	Unicode bool
+
+	// SyncOutput reports support for synchronized output (DEC private
+	// mode 2026), which lets a frame be drawn without tearing. Only
+	// known from Query.
+	SyncOutput bool
+
+	// KittyKeyboard reports support for the kitty keyboard protocol.
+	// Only known from Query.
+	KittyKeyboard bool
+
+	// Terminal is the name and version the terminal reported to
+	// XTVERSION, e.g. "kitty(0.35.2)", or "" if unknown.
+	Terminal string
+
+	// explicit records that NO_COLOR or COLORTERM fixed Colors, so
+	// terminfo and query results don't override it.
+	explicit bool
+}
+
+// Detect returns the capabilities of the terminal described by the
+// process environment and its terminfo entry.
+func Detect() Caps {
+	c := FromEnv(os.Getenv)
+	if ti, err := LoadTerminfo(os.Getenv("TERM")); err == nil {
+		c = c.WithTerminfo(ti)
+	}
+	return c
+}
+
+// FromEnv derives capabilities from environment variables alone; getenv
+// is usually os.Getenv. Without any hint the terminal is assumed to
+// support 256 colors and Unicode.
+func FromEnv(getenv func(string) string) Caps {
+	c := Caps{Colors: ANSI256, Unicode: true}
+	term := getenv("TERM")
+	switch {
+	case term == "dumb":
+		c.Colors = Mono
+	case term == "linux" || term == "ansi" || term == "cons25" || strings.HasPrefix(term, "vt"):
+		c.Colors = ANSI16
+	case strings.HasSuffix(term, "-direct") || strings.HasSuffix(term, "-truecolor"):
+		c.Colors = TrueColor
+	}
+	if term == "dumb" || strings.HasPrefix(term, "vt") {
+		c.Unicode = false
+	}
+
+	// The locale's codeset decides Unicode when one is named; "C" and
+	// "POSIX" are left alone since modern terminals are UTF-8 anyway.
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := getenv(name); v != "" {
+			if _, codeset, ok := strings.Cut(v, "."); ok {
+				codeset, _, _ = strings.Cut(codeset, "@")
+				codeset = strings.ToLower(strings.ReplaceAll(codeset, "-", ""))
+				c.Unicode = codeset == "utf8"
+			}
+			break
+		}
+	}
+
+	if ct := getenv("COLORTERM"); ct == "truecolor" || ct == "24bit" {
+		c.Colors, c.explicit = TrueColor, true
+	}
+	// https://no-color.org: any non-empty value disables color.
+	if getenv("NO_COLOR") != "" {
+		c.Colors, c.explicit = Mono, true
+	}
+	return c
+}
+
+// WithTerminfo refines c with a terminfo entry's color support, unless
+// the environment set the color level explicitly.
+func (c Caps) WithTerminfo(ti *Terminfo) Caps {
+	if c.explicit {
+		return c
+	}
+	switch {
+	case ti.TrueColor:
+		c.Colors = TrueColor
+	case ti.Colors >= 256:
+		c.Colors = ANSI256
+	case ti.Colors >= 8:
+		c.Colors = ANSI16
+	default:
+		c.Colors = Mono
+	}
+	return c
+}
+
+// trueColorTerminals are XTVERSION name prefixes of terminals known to
+// support 24-bit color.
+var trueColorTerminals = []string{
+	"kitty", "WezTerm", "iTerm2", "foot", "ghostty", "contour", "Konsole", "Alacritty",
+}
+
+// withTerminal records the XTVERSION reply and upgrades colors for
+// terminals known to support truecolor.
+func (c Caps) withTerminal(name string) Caps {
+	c.Terminal = name
+	if c.explicit || c.Colors == Mono {
+		return c
+	}
+	for _, prefix := range trueColorTerminals {
+		if strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix)) {
+			c.Colors = TrueColor
+			break
+		}
+	}
+	return c
+}
@@ -0,0 +1,160 @@
+package termcap
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(k string) string { return vars[k] }
+}
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		vars    map[string]string
+		colors  ColorLevel
+		unicode bool
+	}{
+		{"unset", nil, ANSI256, true},
+		{"xterm-256color", map[string]string{"TERM": "xterm-256color", "LANG": "en_US.UTF-8"}, ANSI256, true},
+		{"colorterm", map[string]string{"TERM": "xterm", "COLORTERM": "truecolor"}, TrueColor, true},
+		{"direct", map[string]string{"TERM": "xterm-direct"}, TrueColor, true},
+		{"linux console", map[string]string{"TERM": "linux"}, ANSI16, true},
+		{"vt100", map[string]string{"TERM": "vt100"}, ANSI16, false},
+		{"dumb", map[string]string{"TERM": "dumb"}, Mono, false},
+		{"no color", map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor", "NO_COLOR": "1"}, Mono, true},
+		{"latin1 locale", map[string]string{"LANG": "de_DE.ISO-8859-1"}, ANSI256, false},
+		{"LC_ALL wins", map[string]string{"LC_ALL": "en_GB.utf8", "LANG": "de_DE.ISO-8859-1"}, ANSI256, true},
+		{"C locale", map[string]string{"LANG": "C"}, ANSI256, true},
+	}
+	for _, tt := range tests {
+		c := FromEnv(env(tt.vars))
+		if c.Colors != tt.colors || c.Unicode != tt.unicode {
+			t.Errorf("%s: colors %v unicode %v, want %v %v", tt.name, c.Colors, c.Unicode, tt.colors, tt.unicode)
+		}
+	}
+}
+
+func TestWithTerminfo(t *testing.T) {
+	base := FromEnv(env(map[string]string{"TERM": "xterm"}))
+	if c := base.WithTerminfo(&Terminfo{Colors: 8}); c.Colors != ANSI16 {
+		t.Errorf("8 colors → %v", c.Colors)
+	}
+	if c := base.WithTerminfo(&Terminfo{Colors: 256, TrueColor: true}); c.Colors != TrueColor {
+		t.Errorf("RGB → %v", c.Colors)
+	}
+	if c := base.WithTerminfo(&Terminfo{Colors: -1}); c.Colors != Mono {
+		t.Errorf("no colors → %v", c.Colors)
+	}
+	// NO_COLOR beats terminfo.
+	noColor := FromEnv(env(map[string]string{"TERM": "xterm", "NO_COLOR": "1"}))
+	if c := noColor.WithTerminfo(&Terminfo{Colors: 256}); c.Colors != Mono {
+		t.Errorf("NO_COLOR overridden: %v", c.Colors)
+	}
+}
+
+// compile builds a compiled terminfo entry with the given numeric
+// capabilities and extended boolean capabilities.
+func compile(magic int, names string, nums []int, extBools []string) []byte {
+	var b []byte
+	short := func(v int) { b = binary.LittleEndian.AppendUint16(b, uint16(int16(v))) }
+	align := func() {
+		if len(b)%2 == 1 {
+			b = append(b, 0)
+		}
+	}
+	number := func(v int) {
+		if magic == magic32 {
+			b = binary.LittleEndian.AppendUint32(b, uint32(int32(v)))
+		} else {
+			short(v)
+		}
+	}
+	names += "\x00"
+	for _, v := range []int{magic, len(names), 1, len(nums), 0, 0} {
+		short(v)
+	}
+	b = append(b, names...)
+	b = append(b, 1) // one standard boolean
+	align()
+	for _, v := range nums {
+		number(v)
+	}
+	if len(extBools) == 0 {
+		return b
+	}
+	align()
+	var table []byte
+	var offsets []int
+	for _, name := range extBools {
+		offsets = append(offsets, len(table))
+		table = append(table, name+"\x00"...)
+	}
+	for _, v := range []int{len(extBools), 0, 0, len(offsets), len(table)} {
+		short(v)
+	}
+	for range extBools {
+		b = append(b, 1)
+	}
+	align()
+	for _, off := range offsets {
+		short(off)
+	}
+	return append(b, table...)
+}
+
+func TestParseTerminfo(t *testing.T) {
+	nums := make([]int, 15)
+	for i := range nums {
+		nums[i] = -1
+	}
+	nums[colorsIndex] = 256
+
+	ti, err := ParseTerminfo(compile(magic16, "xterm-256color|xterm with 256 colors", nums, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ti.Names[0] != "xterm-256color" || ti.Colors != 256 || ti.TrueColor {
+		t.Errorf("legacy entry = %+v", ti)
+	}
+
+	ti, err = ParseTerminfo(compile(magic32, "xterm-direct", nums, []string{"AX", "RGB"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ti.Colors != 256 || !ti.TrueColor {
+		t.Errorf("extended entry = %+v", ti)
+	}
+
+	if _, err := ParseTerminfo([]byte{1, 2, 3}); err == nil {
+		t.Error("expected an error for a malformed entry")
+	}
+	if _, err := ParseTerminfo(compile(magic16, "x", nums, nil)[:20]); err == nil {
+		t.Error("expected an error for a truncated entry")
+	}
+}
+
+func TestLoadTerminfo(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TERMINFO", dir)
+	nums := make([]int, 14)
+	nums[colorsIndex] = 8
+	if err := os.MkdirAll(filepath.Join(dir, "6d"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "6d", "mine"), compile(magic16, "mine", nums, nil), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ti, err := LoadTerminfo("mine")
+	if err != nil || ti.Colors != 8 {
+		t.Fatalf("LoadTerminfo = %+v, %v", ti, err)
+	}
+	for _, name := range []string{"", "../etc/passwd", "no-such-terminal-xyz"} {
+		if _, err := LoadTerminfo(name); err == nil {
+			t.Errorf("LoadTerminfo(%q) succeeded", name)
+		}
+	}
+}
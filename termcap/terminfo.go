@@ -0,0 +1,174 @@
+package termcap
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Terminfo is the part of a compiled terminfo entry tooey uses.
+type Terminfo struct {
+	Names     []string // the terminal's name and aliases
+	Colors    int      // the "colors" capability, or -1 if absent
+	TrueColor bool     // the "RGB" or "Tc" extended capability
+}
+
+// Magic numbers of the legacy (16-bit numbers) and extended (32-bit
+// numbers) compiled formats.
+const (
+	magic16 = 0o432
+	magic32 = 0o1036
+)
+
+// colorsIndex is the position of "colors" among the standard numeric
+// capabilities.
+const colorsIndex = 13
+
+// terminfoDirs returns the directories searched for compiled entries,
+// in the order ncurses uses.
+func terminfoDirs() []string {
+	var dirs []string
+	if d := os.Getenv("TERMINFO"); d != "" {
+		dirs = append(dirs, d)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".terminfo"))
+	}
+	for _, d := range strings.Split(os.Getenv("TERMINFO_DIRS"), ":") {
+		if d == "" {
+			d = "/usr/share/terminfo"
+		}
+		dirs = append(dirs, d)
+	}
+	return append(dirs, "/etc/terminfo", "/lib/terminfo", "/usr/share/terminfo", "/usr/lib/terminfo")
+}
+
+// LoadTerminfo finds and parses the compiled terminfo entry for term.
+func LoadTerminfo(term string) (*Terminfo, error) {
+	if term == "" || strings.ContainsAny(term, "/\\") || strings.HasPrefix(term, ".") {
+		return nil, fmt.Errorf("termcap: invalid terminal name %q", term)
+	}
+	for _, dir := range terminfoDirs() {
+		// Entries live under their first letter, or its hex code on
+		// case-insensitive filesystems such as macOS.
+		for _, sub := range []string{term[:1], fmt.Sprintf("%02x", term[0])} {
+			data, err := os.ReadFile(filepath.Join(dir, sub, term))
+			if err == nil {
+				return ParseTerminfo(data)
+			}
+		}
+	}
+	return nil, fmt.Errorf("termcap: no terminfo entry for %q", term)
+}
+
+var errTerminfo = errors.New("termcap: malformed terminfo entry")
+
+// ParseTerminfo decodes a compiled terminfo entry, as documented in
+// term(5).
+func ParseTerminfo(data []byte) (*Terminfo, error) {
+	r := terminfoReader{data: data}
+	magic := r.short()
+	numSize := 2
+	switch magic {
+	case magic16:
+	case magic32:
+		numSize = 4
+	default:
+		return nil, errTerminfo
+	}
+	namesSize, boolCount, numCount, strCount, tableSize := r.short(), r.short(), r.short(), r.short(), r.short()
+	names := r.bytes(namesSize)
+	r.skip(boolCount)
+	r.align()
+	nums := r.numbers(numCount, numSize)
+	r.skip(2*strCount + tableSize)
+	if r.err {
+		return nil, errTerminfo
+	}
+
+	ti := &Terminfo{Names: strings.Split(strings.TrimRight(string(names), "\x00"), "|"), Colors: -1}
+	if colorsIndex < len(nums) && nums[colorsIndex] >= 0 {
+		ti.Colors = nums[colorsIndex]
+	}
+
+	// The extended section follows, aligned to an even offset. Its
+	// capability names are the last strings of its string table.
+	r.align()
+	if r.pos >= len(r.data) {
+		return ti, nil
+	}
+	extBools, extNums, extStrs, extOffsets, extTableSize := r.short(), r.short(), r.short(), r.short(), r.short()
+	bools := r.bytes(extBools)
+	r.align()
+	r.numbers(extNums, numSize)
+	r.skip(2 * extOffsets)
+	table := r.bytes(extTableSize)
+	if r.err {
+		return ti, nil // tolerate a damaged extension
+	}
+	strs := strings.Split(strings.TrimSuffix(string(table), "\x00"), "\x00")
+	nameCount := extBools + extNums + extStrs
+	if len(strs) < nameCount {
+		return ti, nil
+	}
+	for i, name := range strs[len(strs)-nameCount:][:extBools] {
+		if (name == "RGB" || name == "Tc") && bools[i] == 1 {
+			ti.TrueColor = true
+		}
+	}
+	return ti, nil
+}
+
+// terminfoReader reads little-endian fields, recording overruns in err
+// rather than failing each call.
+type terminfoReader struct {
+	data []byte
+	pos  int
+	err  bool
+}
+
+func (r *terminfoReader) bytes(n int) []byte {
+	if n < 0 || r.pos+n > len(r.data) {
+		r.err = true
+		r.pos = len(r.data)
+		return nil
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *terminfoReader) skip(n int) { r.bytes(n) }
+
+func (r *terminfoReader) align() {
+	if r.pos%2 == 1 && r.pos < len(r.data) {
+		r.pos++
+	}
+}
+
+func (r *terminfoReader) short() int {
+	b := r.bytes(2)
+	if b == nil {
+		return 0
+	}
+	return int(int16(binary.LittleEndian.Uint16(b)))
+}
+
+func (r *terminfoReader) numbers(n, size int) []int {
+	b := r.bytes(n * size)
+	if b == nil {
+		return nil
+	}
+	nums := make([]int, n)
+	for i := range nums {
+		if size == 4 {
+			nums[i] = int(int32(binary.LittleEndian.Uint32(b[4*i:])))
+		} else {
+			nums[i] = int(int16(binary.LittleEndian.Uint16(b[2*i:])))
+		}
+	}
+	return nums
+}
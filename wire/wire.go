@@ -36,11 +36,14 @@ type Props struct {
 	FG             *Color  `json:"fg,omitempty"`
 	BG             *Color  `json:"bg,omitempty"`
 	Style          []string `json:"style,omitempty"`
+	UnderlineColor *Color  `json:"underlineColor,omitempty"`
 	ScrollOffset   int     `json:"scrollOffset,omitempty"`
 	ScrollToBottom bool    `json:"scrollToBottom,omitempty"`
 	Padding        *[4]int `json:"padding,omitempty"` // top, right, bottom, left
 	NoWrap         bool    `json:"noWrap,omitempty"`
 	FocusScope     bool    `json:"focusScope,omitempty"`
+	PassThrough    bool    `json:"passThrough,omitempty"`
+	Link           string  `json:"link,omitempty"`
 }
 
 // Color wraps node.Color with a wire-friendly JSON form: a palette
@@ -105,6 +108,10 @@ var typeNames = map[node.NodeType]string{
 	node.PaneNode:    "pane",
 	node.SpacerNode:  "spacer",
 	node.OverlayNode: "overlay",
+	// A canvas's draw callback is Go code and has no wire form; it
+	// round-trips as an empty surface.
+	node.CanvasNode: "canvas",
+	node.RuleNode:   "rule",
 }
 
 var typeValues = invert(typeNames)
@@ -127,6 +134,14 @@ var styleNames = []struct {
 	{node.Italic, "italic"},
 	{node.Underline, "underline"},
 	{node.Reverse, "reverse"},
+	{node.Strikethrough, "strikethrough"},
+	{node.Blink, "blink"},
+	{node.Overline, "overline"},
+	{node.Hidden, "hidden"},
+	{node.DoubleUnderline, "doubleUnderline"},
+	{node.CurlyUnderline, "curlyUnderline"},
+	{node.DottedUnderline, "dottedUnderline"},
+	{node.DashedUnderline, "dashedUnderline"},
 }
 
 func invert[K comparable, V comparable](m map[K]V) map[V]K {
@@ -162,6 +177,8 @@ func fromProps(p node.Props) *Props {
 		ScrollToBottom: p.ScrollToBottom,
 		NoWrap:         p.NoWrap,
 		FocusScope:     p.FocusScope,
+		PassThrough:    p.PassThrough,
+		Link:           p.Link,
 	}
 	if !p.FG.IsDefault() {
 		wp.FG = &Color{p.FG}
@@ -169,6 +186,9 @@ func fromProps(p node.Props) *Props {
 	if !p.BG.IsDefault() {
 		wp.BG = &Color{p.BG}
 	}
+	if !p.UnderlineColor.IsDefault() {
+		wp.UnderlineColor = &Color{p.UnderlineColor}
+	}
 	for _, s := range styleNames {
 		if p.Style&s.flag != 0 {
 			wp.Style = append(wp.Style, s.name)
@@ -227,6 +247,8 @@ func (wp Props) toProps() (node.Props, error) {
 		ScrollToBottom: wp.ScrollToBottom,
 		NoWrap:         wp.NoWrap,
 		FocusScope:     wp.FocusScope,
+		PassThrough:    wp.PassThrough,
+		Link:           wp.Link,
 	}
 	if wp.FG != nil {
 		p.FG = wp.FG.Color
@@ -234,6 +256,9 @@ func (wp Props) toProps() (node.Props, error) {
 	if wp.BG != nil {
 		p.BG = wp.BG.Color
 	}
+	if wp.UnderlineColor != nil {
+		p.UnderlineColor = wp.UnderlineColor.Color
+	}
 	for _, name := range wp.Style {
 		found := false
 		for _, s := range styleNames {
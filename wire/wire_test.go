@@ -17,7 +17,10 @@ func TestRoundTrip(t *testing.T) {
 			node.Text("ok").WithKey("btn-ok").WithFocusable(),
 			node.Spacer(),
 		),
-		node.Overlay(node.Text("base"), node.Text("layer")),
+		node.HRule().WithFG(8),
+		node.TextStyled("docs", 4, 0, node.Underline).WithLink("https://example.com/docs"),
+		node.TextStyled("typo", 0, 0, node.CurlyUnderline|node.Strikethrough).WithUnderlineColor(node.RGB(255, 0, 0)),
+		node.Overlay(node.Text("base"), node.Text("layer").WithPassThrough()),
 	).WithFlex(1).WithScrollToBottom()
 
 	data, err := Marshal(root)
@@ -34,12 +37,12 @@ func TestRoundTrip(t *testing.T) {
 }
 
 func TestWireFormatShape(t *testing.T) {
-	data, err := Marshal(node.TextStyled("hi", node.RGB(255, 0, 0), 0, node.Bold))
+	data, err := Marshal(node.TextStyled("hi", node.RGB(255, 0, 0), 0, node.Bold).WithLink("https://x.dev"))
 	if err != nil {
 		t.Fatal(err)
 	}
 	s := string(data)
-	for _, want := range []string{`"type":"text"`, `"text":"hi"`, `"fg":"#ff0000"`, `"style":["bold"]`} {
+	for _, want := range []string{`"type":"text"`, `"text":"hi"`, `"fg":"#ff0000"`, `"style":["bold"]`, `"link":"https://x.dev"`} {
 		if !strings.Contains(s, want) {
 			t.Errorf("wire JSON missing %s: %s", want, s)
 		}